@@ -0,0 +1,25 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+)
+
+var errNilCertificate = errors.New("crypto/rsa: nil certificate")
+
+// VerifyPSSCert is like VerifyPSS but takes the public key from cert. It
+// fails if cert does not hold an RSA key. The certificate itself is not
+// checked: the caller has to verify its chain, validity and key usage.
+func VerifyPSSCert(cert *x509.Certificate, hash crypto.Hash, hashed []byte, sig []byte, sLen int, opts ...Option) error {
+	if cert == nil {
+		return errNilCertificate
+	}
+	pub, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("crypto/rsa: certificate has a %T public key, not an RSA key", cert.PublicKey)
+	}
+	return VerifyPSS(pub, hash, hashed, sig, sLen, opts...)
+}
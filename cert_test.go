@@ -0,0 +1,62 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func selfSignedCert(t *testing.T, pub, priv interface{}) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "pss test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, pub, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestVerifyPSSCert(t *testing.T) {
+	priv := testKey(t)
+	cert := selfSignedCert(t, &priv.PublicKey, priv)
+	hashed := sha256.Sum256([]byte("certificate"))
+	sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyPSSCert(cert, crypto.SHA256, hashed[:], sig, sha256.Size); err != nil {
+		t.Errorf("valid signature: %v", err)
+	}
+	sig[0] ^= 1
+	if err := VerifyPSSCert(cert, crypto.SHA256, hashed[:], sig, sha256.Size); err != rsa.ErrVerification {
+		t.Errorf("bad signature: got %v, want rsa.ErrVerification", err)
+	}
+
+	ecPriv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecCert := selfSignedCert(t, &ecPriv.PublicKey, ecPriv)
+	if err := VerifyPSSCert(ecCert, crypto.SHA256, hashed[:], sig, sha256.Size); err == nil {
+		t.Errorf("ECDSA certificate accepted")
+	}
+	if err := VerifyPSSCert(nil, crypto.SHA256, hashed[:], sig, sha256.Size); err == nil {
+		t.Errorf("nil certificate accepted")
+	}
+}
@@ -0,0 +1,39 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"encoding/binary"
+	"io"
+)
+
+// contextDigest returns the hash of
+//
+//	len(context) || context || message
+//
+// with the length as an 8 byte big-endian integer, so that no choice of
+// context and message can be read as another.
+func contextDigest(hash crypto.Hash, context string, message []byte) []byte {
+	h := hash.New()
+	var n [8]byte
+	binary.BigEndian.PutUint64(n[:], uint64(len(context)))
+	h.Write(n[:])
+	io.WriteString(h, context)
+	h.Write(message)
+	return h.Sum(nil)
+}
+
+// SignPSSContextString signs message under context, a string naming the
+// application or purpose of the signature. The digest signed is the hash
+// of the length of context as an 8 byte big-endian integer, context and
+// message, so a signature made under one context does not verify under
+// another even for the same message. salt is used as in SignPSS.
+func SignPSSContextString(rand io.Reader, priv *rsa.PrivateKey, hash crypto.Hash, context string, message []byte, salt []byte, opts ...Option) ([]byte, error) {
+	return SignPSS(rand, priv, hash, contextDigest(hash, context, message), salt, opts...)
+}
+
+// VerifyPSSContextString verifies sig as a signature of message made by
+// SignPSSContextString under context.
+func VerifyPSSContextString(pub *rsa.PublicKey, hash crypto.Hash, context string, message []byte, sig []byte, sLen int, opts ...Option) error {
+	return VerifyPSS(pub, hash, contextDigest(hash, context, message), sig, sLen, opts...)
+}
@@ -0,0 +1,32 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+)
+
+func TestSignPSSContextString(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	msg := []byte("transfer 100")
+	salt := []byte("context salt")
+	sig, err := SignPSSContextString(rand.Reader, priv, crypto.SHA256, "payments/v1", msg, salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyPSSContextString(pub, crypto.SHA256, "payments/v1", msg, sig, len(salt)); err != nil {
+		t.Errorf("same context: %v", err)
+	}
+	if err := VerifyPSSContextString(pub, crypto.SHA256, "audit/v1", msg, sig, len(salt)); err != rsa.ErrVerification {
+		t.Errorf("other context: got %v, want rsa.ErrVerification", err)
+	}
+	// Moving bytes between the context and the message must not help.
+	if err := VerifyPSSContextString(pub, crypto.SHA256, "payments/v1t", msg[1:], sig, len(salt)); err != rsa.ErrVerification {
+		t.Errorf("shifted boundary: got %v, want rsa.ErrVerification", err)
+	}
+	if err := VerifyPSS(pub, crypto.SHA256, contextDigest(crypto.SHA256, "", msg), sig, len(salt)); err != rsa.ErrVerification {
+		t.Errorf("empty context: got %v, want rsa.ErrVerification", err)
+	}
+}
@@ -0,0 +1,108 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// A SignerSig is one signature among several over the same message. Each
+// signer may use its own parameters.
+type SignerSig struct {
+	PublicKey  *rsa.PublicKey
+	Signature  []byte
+	SaltLength int
+
+	// Hash is the hash function of the signature. Zero selects the hash
+	// passed to VerifyPSSAll.
+	Hash crypto.Hash
+
+	// MGFHash, if not zero, is the hash function used for MGF1; see
+	// WithMGFHash.
+	MGFHash crypto.Hash
+}
+
+// A SignerFailure records why the signature at Index did not verify.
+type SignerFailure struct {
+	Index int
+	Err   error
+}
+
+// A CoSignError is returned by VerifyPSSAll when some of the signatures
+// do not verify. It lists every failing signer.
+type CoSignError struct {
+	Failures []SignerFailure
+	// Verified holds the indices of the signatures that did verify.
+	Verified []int
+	// Total is the number of signatures that were checked.
+	Total int
+}
+
+func (e *CoSignError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "crypto/rsa: %d of %d signatures failed to verify", len(e.Failures), e.Total)
+	for i, f := range e.Failures {
+		sep := "; "
+		if i == 0 {
+			sep = ": "
+		}
+		fmt.Fprintf(&b, "%ssigner %d: %v", sep, f.Index, f.Err)
+	}
+	return b.String()
+}
+
+// Unwrap returns the errors of the failing signers.
+func (e *CoSignError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, f := range e.Failures {
+		errs[i] = f.Err
+	}
+	return errs
+}
+
+// VerifyPSSAll verifies that every signature in sigs is a valid RSASSA-PSS
+// signature over message. The message is hashed once for every distinct
+// hash function among the signers, with hash for those that do not set
+// one. It returns nil only if all of them verify, and otherwise a
+// *CoSignError.
+func VerifyPSSAll(message []byte, hash crypto.Hash, sigs []SignerSig) error {
+	if len(sigs) == 0 {
+		return errors.New("crypto/rsa: no signatures to verify")
+	}
+	digests := make(map[crypto.Hash][]byte)
+
+	var failures []SignerFailure
+	var verified []int
+	for i, s := range sigs {
+		h := s.Hash
+		if h == 0 {
+			h = hash
+		}
+		if !h.Available() {
+			failures = append(failures, SignerFailure{Index: i, Err: fmt.Errorf("crypto/rsa: hash function %v not available", h)})
+			continue
+		}
+		if s.MGFHash != 0 && !s.MGFHash.Available() {
+			failures = append(failures, SignerFailure{Index: i, Err: fmt.Errorf("crypto/rsa: MGF1 hash function %v not available", s.MGFHash)})
+			continue
+		}
+		hashed, ok := digests[h]
+		if !ok {
+			d := h.New()
+			d.Write(message)
+			hashed = d.Sum(nil)
+			digests[h] = hashed
+		}
+		if err := VerifyPSS(s.PublicKey, h, hashed, s.Signature, s.SaltLength, WithMGFHash(s.MGFHash)); err != nil {
+			failures = append(failures, SignerFailure{Index: i, Err: err})
+			continue
+		}
+		verified = append(verified, i)
+	}
+	if failures != nil {
+		return &CoSignError{Failures: failures, Verified: verified, Total: len(sigs)}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"testing"
+)
+
+func TestVerifyPSSAll(t *testing.T) {
+	msg := []byte("multi-party manifest")
+	hashed := sha256.Sum256(msg)
+	var sigs []SignerSig
+	for i := 0; i < 3; i++ {
+		priv, err := rsa.GenerateKey(rand.Reader, 1024)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], i*8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs = append(sigs, SignerSig{PublicKey: &priv.PublicKey, Signature: sig, SaltLength: i * 8})
+	}
+	if err := VerifyPSSAll(msg, crypto.SHA256, sigs); err != nil {
+		t.Fatalf("valid co-signatures: %v", err)
+	}
+
+	sigs[1].Signature[0] ^= 0x80
+	err := VerifyPSSAll(msg, crypto.SHA256, sigs)
+	var cerr *CoSignError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("got %v, want a *CoSignError", err)
+	}
+	if len(cerr.Failures) != 1 || cerr.Failures[0].Index != 1 {
+		t.Errorf("got failures %+v, want only signer 1", cerr.Failures)
+	}
+	if !errors.Is(err, rsa.ErrVerification) {
+		t.Errorf("error does not wrap rsa.ErrVerification")
+	}
+
+	if err := VerifyPSSAll(msg, crypto.SHA256, nil); err == nil {
+		t.Errorf("no signatures verified")
+	}
+}
+
+func TestVerifyPSSAllMixedParameters(t *testing.T) {
+	msg := []byte("heterogeneous signers")
+	priv := testKey(t)
+	params := []SignerSig{
+		{SaltLength: 32},
+		{Hash: crypto.SHA384, SaltLength: 48},
+		{Hash: crypto.SHA512, MGFHash: crypto.SHA1, SaltLength: 0},
+		{Hash: crypto.SHA384, SaltLength: 20},
+	}
+	sigs := make([]SignerSig, len(params))
+	for i, p := range params {
+		hash := p.Hash
+		if hash == 0 {
+			hash = crypto.SHA256
+		}
+		h := hash.New()
+		h.Write(msg)
+		sig, err := SignPSSAutoSalt(rand.Reader, priv, hash, h.Sum(nil), p.SaltLength, WithMGFHash(p.MGFHash))
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.PublicKey = &priv.PublicKey
+		p.Signature = sig
+		sigs[i] = p
+	}
+	if err := VerifyPSSAll(msg, crypto.SHA256, sigs); err != nil {
+		t.Fatalf("valid co-signatures: %v", err)
+	}
+
+	sigs[2].MGFHash = 0
+	sigs[3].Hash = crypto.SHA256
+	err := VerifyPSSAll(msg, crypto.SHA256, sigs)
+	var cerr *CoSignError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("got %v, want a *CoSignError", err)
+	}
+	if len(cerr.Failures) != 2 || cerr.Failures[0].Index != 2 || cerr.Failures[1].Index != 3 {
+		t.Errorf("got failures %+v, want signers 2 and 3", cerr.Failures)
+	}
+	if len(cerr.Verified) != 2 || cerr.Verified[0] != 0 || cerr.Verified[1] != 1 {
+		t.Errorf("got verified %v, want signers 0 and 1", cerr.Verified)
+	}
+	sigs[0].MGFHash = crypto.MD4
+	err = VerifyPSSAll(msg, crypto.SHA256, sigs[:2])
+	if !errors.As(err, &cerr) || len(cerr.Failures) != 1 || cerr.Failures[0].Index != 0 {
+		t.Fatalf("unavailable MGF1 hash: got %v", err)
+	}
+	if errors.Is(cerr.Failures[0].Err, rsa.ErrVerification) {
+		t.Errorf("unavailable MGF1 hash reported as a verification failure: %v", cerr.Failures[0].Err)
+	}
+}
@@ -0,0 +1,99 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"encoding/binary"
+	"hash"
+)
+
+// VerifyDebugInfo describes the intermediate values of one verification,
+// for comparing them with those of another implementation when chasing an
+// interoperability bug. Fields that verification did not get to are nil
+// or zero.
+type VerifyDebugInfo struct {
+	// EM is the encoded message, the signature raised to the public
+	// exponent, before unmasking.
+	EM []byte
+
+	// H is the hash field of EM, the seed of MGF1.
+	H []byte
+
+	// Mask is the dbMask generated by MGF1, the concatenation of its
+	// blocks truncated to the length of the data block.
+	Mask []byte
+
+	// DB is the unmasked data block, with its leftmost bits cleared.
+	DB []byte
+
+	// MGF1Blocks is the number of MGF1 blocks that were computed, and
+	// MGF1Counters the counter that was hashed for each, in order.
+	MGF1Blocks   int
+	MGF1Counters []uint32
+}
+
+// DebugVerifyPSS verifies sig as VerifyPSS does and also returns the
+// intermediate values of the verification. It is slower than VerifyPSS, and
+// the information it returns is meant for debugging only. info is nil if
+// verification failed before the signature was decoded.
+func DebugVerifyPSS(pub *rsa.PublicKey, hash crypto.Hash, hashed []byte, sig []byte, sLen int, opts ...Option) (info *VerifyDebugInfo, err error) {
+	o := newOptions(opts)
+	rec := new(mgf1Recorder)
+	o.mgfWrap = rec.wrap
+
+	sc := new(verifyScratch)
+	em, err := decodePSS(pub.N, sc.e.SetInt64(int64(pub.E)), sig, sc, o)
+	if err != nil {
+		return nil, err
+	}
+	info = &VerifyDebugInfo{EM: append([]byte(nil), em...)}
+	o.warnIfRawMessage(hashed)
+	err = emsaPSSVerify(hashed, em, pub.N.BitLen()-1, sLen, hash.New, o)
+
+	dbLen := len(em) - hash.Size() - len(o.trailerField())
+	if dbLen < 0 {
+		return info, err
+	}
+	info.H = info.EM[dbLen : dbLen+hash.Size()]
+	if len(rec.counters) > 0 {
+		info.DB = em[:dbLen]
+		info.Mask = rec.mask[:min(len(rec.mask), dbLen)]
+		info.MGF1Blocks = len(rec.counters)
+		info.MGF1Counters = rec.counters
+	}
+	return info, err
+}
+
+// mgf1Recorder collects the blocks of MGF1 as recordingHash sees them.
+type mgf1Recorder struct {
+	last     []byte
+	mask     []byte
+	counters []uint32
+}
+
+func (r *mgf1Recorder) wrap(h hash.Hash) hash.Hash {
+	return recordingHash{h, r}
+}
+
+// recordingHash passes everything to the hash it wraps and records every
+// digest, with the counter, which MGF1 writes last before each Sum. It hides
+// the state marshaling methods of the wrapped hash, so MGF1 writes the seed
+// and the counter for every block.
+type recordingHash struct {
+	hash.Hash
+	rec *mgf1Recorder
+}
+
+func (h recordingHash) Write(p []byte) (int, error) {
+	h.rec.last = append(h.rec.last[:0], p...)
+	return h.Hash.Write(p)
+}
+
+func (h recordingHash) Sum(b []byte) []byte {
+	out := h.Hash.Sum(b)
+	if len(h.rec.last) == 4 {
+		h.rec.counters = append(h.rec.counters, binary.BigEndian.Uint32(h.rec.last))
+	}
+	h.rec.mask = append(h.rec.mask, out[len(b):]...)
+	return out
+}
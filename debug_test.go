@@ -0,0 +1,71 @@
+package pss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"io"
+	"math/big"
+	"testing"
+)
+
+func TestDebugVerifyPSS(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	hashed := sha256.Sum256([]byte("debug"))
+	salt := []byte("salt")
+	sig, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := DebugVerifyPSS(pub, crypto.SHA256, hashed[:], sig, len(salt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := ComputeExpectedEM(pub, crypto.SHA256, hashed[:], salt)
+	if !bytes.Equal(info.EM, want) {
+		t.Errorf("EM differs from ComputeExpectedEM")
+	}
+	dbLen := len(want) - sha256.Size - 1
+	if !bytes.Equal(info.H, want[dbLen:dbLen+sha256.Size]) {
+		t.Errorf("H is not the hash field of EM")
+	}
+	mask := make([]byte, dbLen)
+	io.ReadFull(NewMGF1Reader(sha256.New(), info.H), mask)
+	if !bytes.Equal(info.Mask, mask) {
+		t.Errorf("mask differs from MGF1 of H")
+	}
+	if !bytes.HasSuffix(info.DB, append([]byte{0x01}, salt...)) {
+		t.Errorf("DB does not end in 0x01 || salt")
+	}
+	// 223 bytes of mask take 7 blocks of SHA-256.
+	if info.MGF1Blocks != 7 || len(info.MGF1Counters) != 7 {
+		t.Fatalf("%d MGF1 blocks, counters %v; want 7", info.MGF1Blocks, info.MGF1Counters)
+	}
+	for i, c := range info.MGF1Counters {
+		if c != uint32(i) {
+			t.Errorf("block %d has counter %d", i, c)
+		}
+	}
+
+	// A bad trailer fails before the mask is generated.
+	em := append([]byte(nil), want...)
+	em[len(em)-1] = 0xCC
+	badSig, err := RawDecrypt(rand.Reader, priv, new(big.Int).SetBytes(em))
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err = DebugVerifyPSS(pub, crypto.SHA256, hashed[:], badSig.FillBytes(make([]byte, len(sig))), len(salt))
+	if err != rsa.ErrVerification {
+		t.Errorf("bad trailer: got %v, want rsa.ErrVerification", err)
+	}
+	if info == nil || !bytes.Equal(info.EM, em) || info.MGF1Blocks != 0 || info.Mask != nil {
+		t.Errorf("bad trailer: got %+v", info)
+	}
+
+	if info, err := DebugVerifyPSS(pub, crypto.SHA256, hashed[:], sig[1:], len(salt)); err == nil || info != nil {
+		t.Errorf("short signature: got %v, %v", info, err)
+	}
+}
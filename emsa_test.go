@@ -1,9 +1,15 @@
 package pss
 
 import (
+	"crypto/rsa"
 	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding"
 	"fmt"
+	"hash"
 	"math/big"
+	"strings"
 	"testing"
 )
 
@@ -39,7 +45,7 @@ func TestEMSAPSS(t *testing.T) {
 	hash.Write(msg)
 	hashed = hash.Sum(hashed[:0])
 
-	encoded, err := emsaPSSEncode(hashed, 1023, salt, sha1.New())
+	encoded, err := emsaPSSEncode(hashed, 1023, salt, sha1.New, newOptions(nil))
 	if err != nil {
 		t.Errorf("Error: %v\n", err)
 	}
@@ -47,7 +53,7 @@ func TestEMSAPSS(t *testing.T) {
 		t.Errorf("Bad encoding")
 	}
 
-	err = emsaPSSVerify(hashed, encoded, 1023, len(salt), sha1.New())
+	err = emsaPSSVerify(hashed, encoded, 1023, len(salt), sha1.New, newOptions(nil))
 	if err != nil {
 		t.Errorf("Bad verification")
 	}
@@ -58,3 +64,296 @@ func fromBase16(base10 string) *big.Int {
 	i.SetString(base10, 16)
 	return i
 }
+
+// Moduli whose bit length sits just around a byte boundary exercise the
+// top-byte masking and the placement of the 0xBC trailer.
+func TestEMSAPSSByteBoundaries(t *testing.T) {
+	hashed := make([]byte, sha1.Size)
+	salt := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+	for _, emBits := range []int{1021, 1022, 1023, 1024, 1025, 2045, 2046, 2047, 2048, 2049, 8191, 8192, 8193, 16383, 16384, 16385} {
+		emLen := (emBits + 7) / 8
+		encoded, err := emsaPSSEncode(hashed, emBits, salt, sha1.New, newOptions(nil))
+		if err != nil {
+			t.Errorf("emBits=%d: encode error: %v", emBits, err)
+			continue
+		}
+		if len(encoded) != emLen {
+			t.Errorf("emBits=%d: got %d bytes, want %d", emBits, len(encoded), emLen)
+		}
+		if encoded[len(encoded)-1] != 0xBC {
+			t.Errorf("emBits=%d: trailer is %x, want bc", emBits, encoded[len(encoded)-1])
+		}
+		if new(big.Int).SetBytes(encoded).BitLen() > emBits {
+			t.Errorf("emBits=%d: encoded message is longer than emBits", emBits)
+		}
+		err = emsaPSSVerify(hashed, encoded, emBits, len(salt), sha1.New, newOptions(nil))
+		if err != nil {
+			t.Errorf("emBits=%d: verify error: %v", emBits, err)
+		}
+	}
+}
+
+func TestEMSAPSSSeparateHashInstances(t *testing.T) {
+	var instances []hash.Hash
+	newHash := func() hash.Hash {
+		h := sha1.New()
+		instances = append(instances, h)
+		return h
+	}
+	hashed := make([]byte, sha1.Size)
+	em, err := emsaPSSEncode(hashed, 1023, []byte("salt"), newHash, newOptions(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 2 || instances[0] == instances[1] {
+		t.Errorf("encode used %d hash instances, want 2 distinct ones", len(instances))
+	}
+	instances = nil
+	if err := emsaPSSVerify(hashed, em, 1023, 4, newHash, newOptions(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 2 || instances[0] == instances[1] {
+		t.Errorf("verify used %d hash instances, want 2 distinct ones", len(instances))
+	}
+}
+
+// tracingHash records the operations performed on it.
+type tracingHash struct {
+	hash.Hash
+	trace *[]string
+}
+
+func (h tracingHash) Write(p []byte) (int, error) {
+	*h.trace = append(*h.trace, fmt.Sprintf("write %d", len(p)))
+	return h.Hash.Write(p)
+}
+
+func (h tracingHash) Sum(b []byte) []byte {
+	*h.trace = append(*h.trace, "sum")
+	return h.Hash.Sum(b)
+}
+
+func (h tracingHash) Reset() {
+	*h.trace = append(*h.trace, "reset")
+	h.Hash.Reset()
+}
+
+func TestEMSAPSSVerifyConstantTime(t *testing.T) {
+	const emBits = 1023
+	hashed := make([]byte, sha1.Size)
+	salt := []byte("0123456789")
+	em, err := emsaPSSEncode(hashed, emBits, salt, sha1.New, newOptions(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	emLen := len(em)
+	// Flipping a bit of maskedDB flips the same bit of DB.
+	mutations := map[string]func(em []byte){
+		"valid":        func(em []byte) {},
+		"trailer":      func(em []byte) { em[emLen-1] ^= 0x01 },
+		"top bits":     func(em []byte) { em[0] ^= 0x80 },
+		"padding":      func(em []byte) { em[1] ^= 0x01 },
+		"separator":    func(em []byte) { em[emLen-sha1.Size-len(salt)-2] ^= 0x01 },
+		"salt":         func(em []byte) { em[emLen-sha1.Size-2] ^= 0x01 },
+		"hash element": func(em []byte) { em[emLen-2] ^= 0x01 },
+	}
+	for _, constantTime := range []bool{true, false} {
+		o := newOptions(nil)
+		if constantTime {
+			o = newOptions([]Option{WithConstantTimeVerify()})
+		}
+		traces := map[string][]string{}
+		for name, mutate := range mutations {
+			var trace []string
+			newHash := func() hash.Hash {
+				return tracingHash{sha1.New(), &trace}
+			}
+			mutated := append([]byte(nil), em...)
+			mutate(mutated)
+			err := emsaPSSVerify(hashed, mutated, emBits, len(salt), newHash, o)
+			if (err == nil) != (name == "valid") {
+				t.Errorf("constantTime=%v, %s: got error %v", constantTime, name, err)
+			}
+			traces[name] = trace
+		}
+		valid := strings.Join(traces["valid"], ",")
+		for name, trace := range traces {
+			same := strings.Join(trace, ",") == valid
+			if constantTime && !same {
+				t.Errorf("%s: operations differ from a valid signature", name)
+			}
+			if !constantTime && name == "trailer" && same {
+				t.Errorf("default mode did not return early on a bad trailer")
+			}
+		}
+	}
+}
+
+// A SHA-512 encoding for a 16384 bit modulus has a data block of almost 2 KiB
+// and a salt that is much longer than the hash.
+func TestEMSAPSSLargeModulus(t *testing.T) {
+	hashed := make([]byte, sha512.Size)
+	for _, emBits := range []int{8191, 16383} {
+		emLen := (emBits + 7) / 8
+		for _, sLen := range []int{sha512.Size, emLen - sha512.Size - 2} {
+			salt := make([]byte, sLen)
+			for i := range salt {
+				salt[i] = byte(i)
+			}
+			encoded, err := emsaPSSEncode(hashed, emBits, salt, sha512.New, newOptions(nil))
+			if err != nil {
+				t.Fatalf("emBits=%d, sLen=%d: %v", emBits, sLen, err)
+			}
+			if encoded[0]>>(8-(8*emLen-emBits)) != 0 {
+				t.Errorf("emBits=%d, sLen=%d: top bits not cleared: %08b", emBits, sLen, encoded[0])
+			}
+			if err := emsaPSSVerify(hashed, encoded, emBits, sLen, sha512.New, newOptions(nil)); err != nil {
+				t.Errorf("emBits=%d, sLen=%d: %v", emBits, sLen, err)
+			}
+		}
+	}
+}
+
+// emsaPSSVerify must reject an em whose length does not match emBits
+// instead of slicing out of range.
+func TestEMSAPSSVerifyBounds(t *testing.T) {
+	hashed := make([]byte, sha1.Size)
+	salt := []byte("salt")
+	em, err := emsaPSSEncode(hashed, 1023, salt, sha1.New, newOptions(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name   string
+		em     []byte
+		emBits int
+		sLen   int
+	}{
+		{"em too short", em[1:], 1023, len(salt)},
+		{"em too long", append([]byte{0}, em...), 1023, len(salt)},
+		{"empty em", nil, 1023, len(salt)},
+		{"emBits too small", em, 100, len(salt)},
+		{"emBits too large", em, 4096, len(salt)},
+		{"zero emBits", em, 0, len(salt)},
+		{"negative emBits", em, -8, len(salt)},
+		{"salt too long", em, 1023, len(em)},
+		{"empty em, auto salt", nil, 0, SaltLengthAuto},
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("%s: panic: %v", test.name, r)
+				}
+			}()
+			err := emsaPSSVerify(hashed, append([]byte(nil), test.em...), test.emBits, test.sLen, sha1.New, newOptions(nil))
+			if err != rsa.ErrVerification {
+				t.Errorf("%s: got %v, want rsa.ErrVerification", test.name, err)
+			}
+		}()
+	}
+}
+
+// TestEMSAPSSVerifyTopBitsBeforeMask checks that an encoded message whose
+// leftmost bits are set is rejected before MGF1 runs, as in step 6 of the
+// RFC, unless constant time verification was asked for.
+func TestEMSAPSSVerifyTopBitsBeforeMask(t *testing.T) {
+	const emBits = 1023
+	hashed := make([]byte, sha1.Size)
+	em, err := emsaPSSEncode(hashed, emBits, nil, sha1.New, newOptions(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	em[0] |= 0x80
+	for _, constantTime := range []bool{false, true} {
+		var trace []string
+		newHash := func() hash.Hash {
+			return tracingHash{sha1.New(), &trace}
+		}
+		o := newOptions(nil)
+		o.constantTime = constantTime
+		if err := emsaPSSVerify(hashed, append([]byte(nil), em...), emBits, 0, newHash, o); err != rsa.ErrVerification {
+			t.Errorf("constantTime=%v: got %v, want rsa.ErrVerification", constantTime, err)
+		}
+		if hashed := len(trace) != 0; hashed != constantTime {
+			t.Errorf("constantTime=%v: hash operations %v", constantTime, trace)
+		}
+	}
+}
+
+func TestEMSAPSSMGFSeedLength(t *testing.T) {
+	hashed := make([]byte, sha1.Size)
+	salt := []byte("salt")
+	want, err := emsaPSSEncode(hashed, 1023, salt, sha1.New, newOptions(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []int{0, sha1.Size} {
+		em, err := emsaPSSEncode(hashed, 1023, salt, sha1.New, newOptions([]Option{WithMGFSeedLength(n)}))
+		if err != nil {
+			t.Fatalf("seed length %d: %v", n, err)
+		}
+		if !compareBytes(em, want) {
+			t.Errorf("seed length %d changes the encoding", n)
+		}
+	}
+
+	short := newOptions([]Option{WithMGFSeedLength(8)})
+	em, err := emsaPSSEncode(hashed, 1023, salt, sha1.New, short)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := emsaPSSVerify(hashed, append([]byte(nil), em...), 1023, len(salt), sha1.New, short); err != nil {
+		t.Errorf("8 byte seed: %v", err)
+	}
+	if err := emsaPSSVerify(hashed, append([]byte(nil), em...), 1023, len(salt), sha1.New, newOptions(nil)); err != rsa.ErrVerification {
+		t.Errorf("8 byte seed verified with the whole of H: got %v, want rsa.ErrVerification", err)
+	}
+
+	for _, n := range []int{-1, sha1.Size + 1} {
+		o := newOptions([]Option{WithMGFSeedLength(n)})
+		if _, err := emsaPSSEncode(hashed, 1023, salt, sha1.New, o); err == nil {
+			t.Errorf("seed length %d: encoding succeeded", n)
+		}
+		if err := emsaPSSVerify(hashed, append([]byte(nil), want...), 1023, len(salt), sha1.New, o); err == nil {
+			t.Errorf("seed length %d: verification succeeded", n)
+		}
+	}
+}
+
+// BenchmarkMPrimeHash compares computing H = Hash(M') from scratch with
+// restoring a hash state that has already absorbed the eight zero octets of
+// M'. The restored state is no faster: the prefix is shorter than a block,
+// so the hash only buffers it, and restoring costs more than writing eight
+// bytes. Signing therefore does not cache it.
+func BenchmarkMPrimeHash(b *testing.B) {
+	mHash := make([]byte, sha256.Size)
+	salt := make([]byte, sha256.Size)
+	out := make([]byte, 0, sha256.Size)
+	b.Run("write", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			h := sha256.New()
+			h.Write(mPrimePrefix[:])
+			h.Write(mHash)
+			h.Write(salt)
+			out = h.Sum(out[:0])
+		}
+	})
+	b.Run("restore", func(b *testing.B) {
+		h := sha256.New()
+		h.Write(mPrimePrefix[:])
+		state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			h := sha256.New()
+			if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+				b.Fatal(err)
+			}
+			h.Write(mHash)
+			h.Write(salt)
+			out = h.Sum(out[:0])
+		}
+	})
+}
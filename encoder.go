@@ -0,0 +1,66 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// An Encoder computes EMSA-PSS encodings for one key size and hash function.
+// Encode reuses the encoder's encoded message buffer and hash instances
+// from one encoding to the next. SignPSSInto builds the encoded message in
+// the signature buffer and reuses only the hash instances, which saves
+// their allocations when signing at a high rate. An Encoder is not safe for
+// concurrent use; a signer can hold one per key and goroutine.
+type Encoder struct {
+	emBits int
+	em     []byte
+	h      hashFunc
+	hashes [2]hash.Hash
+	next   int
+	opts   *options
+}
+
+// newHash hands out the hash instances in turn, reset, so that H and the
+// mask each get their own.
+func (e *Encoder) newHash() hash.Hash {
+	h := e.hashes[e.next%len(e.hashes)]
+	e.next++
+	h.Reset()
+	return h
+}
+
+// NewEncoder returns an Encoder for keys with the modulus size of pub and
+// for the hash function hash. The options apply to every encoding.
+func NewEncoder(pub *rsa.PublicKey, hash crypto.Hash, opts ...Option) *Encoder {
+	emBits := pub.N.BitLen() - 1
+	o := newOptions(opts)
+	e := &Encoder{emBits: emBits, em: make([]byte, (emBits+7)/8), opts: o}
+	e.hashes[0], e.hashes[1] = hash.New(), hash.New()
+	e.h = cryptoHashFunc(hash)
+	e.h.new = e.newHash
+	return e
+}
+
+// Encode returns the encoded message EM for mHash and salt, as
+// ComputeExpectedEM does. The result stays valid until the next call to
+// Encode.
+func (e *Encoder) Encode(mHash []byte, salt []byte) ([]byte, error) {
+	e.next = 0
+	return emsaPSSEncodeInto(e.em, mHash, e.emBits, salt, e.newHash, e.opts)
+}
+
+// SignPSSInto is like the function SignPSSInto with the hash function and
+// options of e, but encodes with the hash instances of e instead of making
+// new ones. priv must have the modulus size e was made for.
+func (e *Encoder) SignPSSInto(dst []byte, rand io.Reader, priv *rsa.PrivateKey,
+	hashed, salt []byte) ([]byte, error) {
+	if bits := priv.N.BitLen() - 1; bits != e.emBits {
+		return nil, fmt.Errorf("crypto/rsa: encoder is for %d-bit moduli, not %d-bit",
+			e.emBits+1, bits+1)
+	}
+	e.next = 0
+	return signPSSInto(dst, rand, priv, e.h, hashed, salt, e.opts)
+}
@@ -0,0 +1,112 @@
+package pss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestEncoder(t *testing.T) {
+	pub := &testKey(t).PublicKey
+	enc := NewEncoder(pub, crypto.SHA256)
+	for i, salt := range [][]byte{nil, []byte("salt"), bytes.Repeat([]byte{7}, 32), nil} {
+		hashed := sha256.Sum256([]byte{byte(i)})
+		want, err := ComputeExpectedEM(pub, crypto.SHA256, hashed[:], salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := enc.Encode(hashed[:], salt)
+		if err != nil {
+			t.Fatalf("encoding %d: %v", i, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("encoding %d differs from ComputeExpectedEM", i)
+		}
+	}
+	if _, err := enc.Encode(make([]byte, 20), nil); err == nil {
+		t.Errorf("encoding a digest of the wrong size succeeded")
+	}
+}
+
+func TestEncoderSignPSSInto(t *testing.T) {
+	priv := testKey(t)
+	enc := NewEncoder(&priv.PublicKey, crypto.SHA256)
+	hashed := sha256.Sum256([]byte("encoder"))
+	salt := []byte("salt")
+	var sig []byte
+	for i := 0; i < 3; i++ {
+		var err error
+		sig, err = enc.SignPSSInto(sig[:0], rand.Reader, priv, hashed[:], salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyPSS(&priv.PublicKey, crypto.SHA256, hashed[:], sig, len(salt)); err != nil {
+			t.Errorf("signature %d: %v", i, err)
+		}
+	}
+	if _, err := enc.SignPSSInto(nil, rand.Reader, priv, hashed[:20], salt); err == nil {
+		t.Errorf("signing a digest of the wrong size succeeded")
+	}
+	if _, err := enc.SignPSSInto(nil, rand.Reader, selfTestKey(), hashed[:], salt); err == nil {
+		t.Errorf("signing with a key of another size succeeded")
+	}
+
+	withEncoder := testing.AllocsPerRun(10, func() {
+		sig, _ = enc.SignPSSInto(sig[:0], rand.Reader, priv, hashed[:], salt)
+	})
+	without := testing.AllocsPerRun(10, func() {
+		sig, _ = SignPSSInto(sig[:0], rand.Reader, priv, crypto.SHA256, hashed[:], salt)
+	})
+	if withEncoder >= without {
+		t.Errorf("Encoder.SignPSSInto made %v allocations, SignPSSInto %v", withEncoder, without)
+	}
+}
+
+func BenchmarkEncoder(b *testing.B) {
+	pub := &testKey(b).PublicKey
+	hashed := sha256.Sum256([]byte("benchmark"))
+	salt := make([]byte, sha256.Size)
+	b.Run("ComputeExpectedEM", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := ComputeExpectedEM(pub, crypto.SHA256, hashed[:], salt); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Encoder", func(b *testing.B) {
+		enc := NewEncoder(pub, crypto.SHA256)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := enc.Encode(hashed[:], salt); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkEncoderSignPSSInto(b *testing.B) {
+	priv := testKey(b)
+	hashed := sha256.Sum256([]byte("benchmark"))
+	salt := make([]byte, sha256.Size)
+	sig := make([]byte, priv.Size())
+	b.Run("SignPSSInto", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := SignPSSInto(sig, rand.Reader, priv, crypto.SHA256, hashed[:], salt); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Encoder", func(b *testing.B) {
+		enc := NewEncoder(&priv.PublicKey, crypto.SHA256)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := enc.SignPSSInto(sig, rand.Reader, priv, hashed[:], salt); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package pss
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// EncodeSignatureBase64URL encodes sig with the URL-safe base64 alphabet and
+// without padding, as used by JOSE and most web APIs.
+func EncodeSignatureBase64URL(sig []byte) string {
+	return base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// DecodeSignatureBase64URL decodes a signature encoded by
+// EncodeSignatureBase64URL. It rejects padding and characters of the
+// standard base64 alphabet.
+func DecodeSignatureBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+// DecodeSignatureLenient decodes a base64 signature that may have been
+// copied by hand from an email or a log: it ignores white space, including
+// line breaks, anywhere in s, accepts the standard and the URL-safe
+// alphabet and tolerates missing padding. A mix of the two alphabets is
+// rejected.
+func DecodeSignatureLenient(s string) ([]byte, error) {
+	s = strings.Join(strings.Fields(s), "")
+	s = strings.TrimRight(s, "=")
+	if strings.ContainsAny(s, "-_") {
+		if strings.ContainsAny(s, "+/") {
+			return nil, errors.New("crypto/rsa: signature mixes the standard and URL-safe base64 alphabets")
+		}
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+	return base64.RawStdEncoding.DecodeString(s)
+}
+
+// ModulusBitsFromSignature returns the bit length of the modulus that a
+// signature of len(sig) bytes was made with, as far as it can be told
+// without the key. A signature is exactly as long as the modulus, so the
+// result is an upper bound: the modulus has between 8*len(sig)-7 and
+// 8*len(sig) bits, and only the public key tells which.
+func ModulusBitsFromSignature(sig []byte) int {
+	return 8 * len(sig)
+}
+
+// QuickReject reports whether sig cannot be a signature by pub because it
+// is not exactly as long as the modulus. It does no arithmetic, so a server
+// can call it to turn away malformed requests before the modular
+// exponentiation of VerifyPSS. A false result says nothing about whether
+// the signature is valid.
+func QuickReject(pub *rsa.PublicKey, sig []byte) bool {
+	return len(sig) != (pub.N.BitLen()+7)/8
+}
+
+// NormalizeSignature returns sig as a big-endian integer of exactly
+// modulusBytes bytes, the length VerifyPSS expects. It is for signatures
+// from Java, whose BigInteger.toByteArray adds a leading zero sign byte when
+// the top bit is set and drops leading zero bytes otherwise: all leading
+// zero bytes are removed and the value is left-padded with zeros again. An
+// error is returned if the value does not fit in modulusBytes. A value that
+// fits but is not below the modulus is left for VerifyPSS to reject. The
+// result never shares memory with sig.
+func NormalizeSignature(sig []byte, modulusBytes int) ([]byte, error) {
+	if modulusBytes <= 0 {
+		return nil, fmt.Errorf("crypto/rsa: invalid modulus length %d", modulusBytes)
+	}
+	i := 0
+	for i < len(sig) && sig[i] == 0 {
+		i++
+	}
+	if n := len(sig) - i; n > modulusBytes {
+		return nil, fmt.Errorf("crypto/rsa: signature value of %d bytes exceeds the %d byte modulus", n, modulusBytes)
+	}
+	out := make([]byte, modulusBytes)
+	copyWithLeftPad(out, sig[i:])
+	return out, nil
+}
@@ -0,0 +1,142 @@
+package pss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestSignatureBase64URL(t *testing.T) {
+	for n := 0; n < 8; n++ {
+		sig := bytes.Repeat([]byte{0xfb, 0xff, 0x3e}, n)[:n*3-n/2]
+		s := EncodeSignatureBase64URL(sig)
+		if bytes.ContainsAny([]byte(s), "+/=") {
+			t.Errorf("%x encoded as %q, which is not unpadded base64url", sig, s)
+		}
+		got, err := DecodeSignatureBase64URL(s)
+		if err != nil {
+			t.Errorf("decoding %q: %v", s, err)
+			continue
+		}
+		if !bytes.Equal(got, sig) {
+			t.Errorf("round trip of %x gave %x", sig, got)
+		}
+	}
+	for _, s := range []string{"-_8=", "+/8", "a"} {
+		if _, err := DecodeSignatureBase64URL(s); err == nil {
+			t.Errorf("decoding %q succeeded", s)
+		}
+	}
+}
+
+func TestDecodeSignatureLenient(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("pasted signature"))
+	sig, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrap := func(s string) string {
+		var b strings.Builder
+		b.WriteString("  \n")
+		for len(s) > 64 {
+			b.WriteString(s[:64] + "\r\n")
+			s = s[64:]
+		}
+		b.WriteString(s + "\n\t ")
+		return b.String()
+	}
+	for _, s := range []string{
+		base64.StdEncoding.EncodeToString(sig),
+		base64.RawStdEncoding.EncodeToString(sig),
+		base64.URLEncoding.EncodeToString(sig),
+		wrap(base64.StdEncoding.EncodeToString(sig)),
+		wrap(base64.RawURLEncoding.EncodeToString(sig)),
+	} {
+		got, err := DecodeSignatureLenient(s)
+		if err != nil {
+			t.Errorf("decoding %q: %v", s, err)
+			continue
+		}
+		if err := VerifyPSS(&priv.PublicKey, crypto.SHA256, hashed[:], got, 0); err != nil {
+			t.Errorf("decoded %q: %v", s, err)
+		}
+	}
+	for _, s := range []string{"-_8+", "ab=c", "a", "!!!!"} {
+		if _, err := DecodeSignatureLenient(s); err == nil {
+			t.Errorf("decoding %q succeeded", s)
+		}
+	}
+}
+
+func TestModulusBitsFromSignature(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("modulus bits"))
+	sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bits := ModulusBitsFromSignature(sig)
+	if n := priv.N.BitLen(); bits < n || bits-7 > n {
+		t.Errorf("got %d bits for a %d bit modulus", bits, n)
+	}
+}
+
+func TestQuickReject(t *testing.T) {
+	priv := testKey(t)
+	k := (priv.N.BitLen() + 7) / 8
+	for _, n := range []int{0, 1, k - 1, k, k + 1} {
+		if got, want := QuickReject(&priv.PublicKey, make([]byte, n)), n != k; got != want {
+			t.Errorf("%d byte signature: got %v, want %v", n, got, want)
+		}
+	}
+}
+
+func TestNormalizeSignature(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	k := (pub.N.BitLen() + 7) / 8
+	hashed := sha256.Sum256([]byte("from Java"))
+	sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Force a leading zero byte so that stripping it is exercised too.
+	sig[0] = 0
+	for name, in := range map[string][]byte{
+		"exact":      sig,
+		"sign byte":  append([]byte{0}, sig...),
+		"two zeros":  append([]byte{0, 0}, sig...),
+		"stripped":   bytes.TrimLeft(sig, "\x00"),
+		"short by 1": sig[1:],
+	} {
+		got, err := NormalizeSignature(in, k)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !bytes.Equal(got, sig) {
+			t.Errorf("%s: got %x, want %x", name, got, sig)
+		}
+	}
+
+	if got, err := NormalizeSignature(nil, 4); err != nil || !bytes.Equal(got, make([]byte, 4)) {
+		t.Errorf("empty signature: got %x, %v", got, err)
+	}
+	if _, err := NormalizeSignature(append([]byte{1}, sig...), k); err == nil {
+		t.Errorf("value longer than the modulus accepted")
+	}
+	if _, err := NormalizeSignature(sig, 0); err == nil {
+		t.Errorf("zero modulus length accepted")
+	}
+	in := append([]byte(nil), sig...)
+	got, _ := NormalizeSignature(in, k)
+	got[k-1] ^= 1
+	if in[k-1] != sig[k-1] {
+		t.Errorf("result shares memory with the input")
+	}
+}
@@ -0,0 +1,59 @@
+package pss
+
+import (
+	"crypto/rsa"
+	"errors"
+	"math/big"
+	"sync/atomic"
+)
+
+// ErrSignatureFault is returned by signing with WithSelfVerify when the
+// signature does not verify under the public key, which means that the
+// private key operation computed a wrong result. A wrong result computed
+// with the CRT can reveal a factor of the modulus, so the signature is
+// discarded.
+var ErrSignatureFault = errors.New("crypto/rsa: signature failed self-verification")
+
+// A FaultCounter counts the signatures that WithSelfVerify found to be
+// faulty. Any count above zero deserves an alarm: a single fault in the
+// private key operation points to failing hardware or an attack. The zero
+// value is ready to use, and a FaultCounter may be shared by any number of
+// goroutines signing at once.
+type FaultCounter struct {
+	n atomic.Uint64
+}
+
+// Faults returns the number of faulty signatures counted so far.
+func (c *FaultCounter) Faults() uint64 {
+	return c.n.Load()
+}
+
+// WithSelfVerify makes signing check each signature by raising it to the
+// public exponent and comparing the result with the encoded message before
+// returning it, the usual countermeasure against fault attacks on the CRT.
+// A signature that fails the check is not returned; signing fails with
+// ErrSignatureFault and, if counter is not nil, counts the fault in it.
+// The check costs one public key operation per signature.
+func WithSelfVerify(counter *FaultCounter) Option {
+	return func(o *options) {
+		o.selfVerify = true
+		o.faults = counter
+	}
+}
+
+// checkSignature reports whether s is the signature of the encoded message
+// m under the public key of priv, counting a fault if it is not. A key
+// whose exponent is not positive cannot be checked and is not counted.
+func (o *options) checkSignature(priv *rsa.PrivateKey, m, s *big.Int) error {
+	if priv.E <= 0 {
+		return errNonPositiveExponent
+	}
+	e := big.NewInt(int64(priv.E))
+	if new(big.Int).Exp(s, e, priv.N).Cmp(m) == 0 {
+		return nil
+	}
+	if o.faults != nil {
+		o.faults.n.Add(1)
+	}
+	return ErrSignatureFault
+}
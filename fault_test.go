@@ -0,0 +1,80 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+)
+
+// faultyExponentiator computes with math/big but gets the exponentiation
+// modulo p wrong, like a glitch in one half of the CRT.
+type faultyExponentiator struct {
+	p *big.Int
+}
+
+func (e faultyExponentiator) Exp(x, y, m *big.Int) *big.Int {
+	r := new(big.Int).Exp(x, y, m)
+	if m.Cmp(e.p) == 0 {
+		r.Add(r, bigOne)
+	}
+	return r
+}
+
+func TestWithSelfVerify(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("self-verified"))
+	var faults FaultCounter
+	check := WithSelfVerify(&faults)
+	sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size, check)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyPSS(&priv.PublicKey, crypto.SHA256, hashed[:], sig, sha256.Size); err != nil {
+		t.Errorf("self-verified signature: %v", err)
+	}
+	if n := faults.Faults(); n != 0 {
+		t.Errorf("%d faults counted for a correct signature", n)
+	}
+
+	glitch := WithExponentiator(faultyExponentiator{priv.Primes[0]})
+	if _, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size, glitch); err != nil {
+		t.Fatalf("glitched signing without self-verification: %v", err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size, glitch, check)
+			if !errors.Is(err, ErrSignatureFault) || sig != nil {
+				t.Errorf("faulty signature: got %x, %v; want ErrSignatureFault", sig, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := faults.Faults(); n != 4 {
+		t.Errorf("%d faults counted, want 4", n)
+	}
+
+	// A nil counter still rejects the signature.
+	if _, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size, glitch, WithSelfVerify(nil)); err != ErrSignatureFault {
+		t.Errorf("nil counter: got %v, want ErrSignatureFault", err)
+	}
+
+	// A key without a usable exponent is an error, not a fault.
+	o := newOptions([]Option{check})
+	for _, e := range []int{0, -3} {
+		bad := &rsa.PrivateKey{PublicKey: rsa.PublicKey{N: priv.N, E: e}, D: priv.D}
+		if err := o.checkSignature(bad, bigOne, bigOne); err != errNonPositiveExponent {
+			t.Errorf("exponent %d: got %v, want errNonPositiveExponent", e, err)
+		}
+	}
+	if n := faults.Faults(); n != 4 {
+		t.Errorf("%d faults counted after bad exponents, want 4", n)
+	}
+}
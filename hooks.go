@@ -0,0 +1,158 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"io"
+	"math/big"
+	"time"
+)
+
+// SignCost describes the private key operation of one signature.
+type SignCost struct {
+	// Start and End enclose the private key operation, including
+	// blinding but not the check of WithSelfVerify.
+	Start, End time.Time
+	// ModulusBits is the bit length of the modulus of the signing key.
+	ModulusBits int
+	// CRT reports whether the operation used the precomputed CRT values
+	// of the key.
+	CRT bool
+}
+
+// Duration returns the time the private key operation took.
+func (c SignCost) Duration() time.Duration {
+	return c.End.Sub(c.Start)
+}
+
+// measureWarmup is the number of signatures MeasureSignThroughput makes
+// before it starts the clock.
+const measureWarmup = 3
+
+// MeasureSignThroughput signs a fixed digest with priv and hash for about
+// duration and returns the number of signatures per second, for capacity
+// planning on the hardware at hand. Each signature uses a salt of the size
+// of the hash, read from rand like the blinding value, and a few signatures
+// are made before the measurement to warm up caches. At least one signature
+// is timed, however short duration is.
+func MeasureSignThroughput(rand io.Reader, priv *rsa.PrivateKey, hash crypto.Hash, duration time.Duration) (opsPerSec float64, err error) {
+	h := hash.New()
+	h.Write([]byte("MeasureSignThroughput"))
+	hashed := h.Sum(nil)
+	sign := func() error {
+		_, err := SignPSSAutoSalt(rand, priv, hash, hashed, hash.Size())
+		return err
+	}
+	for i := 0; i < measureWarmup; i++ {
+		if err := sign(); err != nil {
+			return 0, err
+		}
+	}
+	start := time.Now()
+	n := 0
+	for n == 0 || time.Since(start) < duration {
+		if err := sign(); err != nil {
+			return 0, err
+		}
+		n++
+	}
+	return float64(n) / time.Since(start).Seconds(), nil
+}
+
+// WithSignCostHook installs a function that the signing functions call with
+// the cost of each private key operation, for example to feed per-key
+// metrics. The hook runs synchronously on the signing goroutine and should
+// return quickly.
+func WithSignCostHook(hook func(SignCost)) Option {
+	return func(o *options) {
+		o.costHook = hook
+	}
+}
+
+// A SignPhase is a step of signature generation reported to a Tracer.
+type SignPhase int
+
+const (
+	// PhaseHashCheck checks that the input has the size of a digest.
+	PhaseHashCheck SignPhase = iota
+	// PhaseEncode computes the EMSA-PSS encoded message.
+	PhaseEncode
+	// PhaseBlinding draws the blinding factor and blinds the encoded
+	// message. It is skipped when signing without blinding.
+	PhaseBlinding
+	// PhaseExponentiation is the private key operation, including the
+	// removal of the blinding factor.
+	PhaseExponentiation
+	// PhasePadding writes the result as a signature of the modulus size.
+	PhasePadding
+)
+
+var signPhaseNames = [...]string{
+	PhaseHashCheck:      "hash check",
+	PhaseEncode:         "encode",
+	PhaseBlinding:       "blinding",
+	PhaseExponentiation: "exponentiation",
+	PhasePadding:        "padding",
+}
+
+func (p SignPhase) String() string {
+	if p < 0 || int(p) >= len(signPhaseNames) {
+		return "unknown phase"
+	}
+	return signPhaseNames[p]
+}
+
+// A Tracer is told when signing starts and ends each phase, for example to
+// record spans in a tracing system. It learns only which phase starts or
+// ends, never the data being processed, so no secret material can leak
+// through it. Its methods run synchronously on the signing goroutine.
+type Tracer interface {
+	StartPhase(p SignPhase)
+	EndPhase(p SignPhase)
+}
+
+// WithTracer installs a Tracer for the signing functions.
+func WithTracer(t Tracer) Option {
+	return func(o *options) {
+		o.tracer = t
+	}
+}
+
+func (o *options) startPhase(p SignPhase) {
+	if o.tracer != nil {
+		o.tracer.StartPhase(p)
+	}
+}
+
+func (o *options) endPhase(p SignPhase) {
+	if o.tracer != nil {
+		o.tracer.EndPhase(p)
+	}
+}
+
+// An Exponentiator computes the modular exponentiations of the private key
+// operation. The exponentiation of math/big, which is the default, is not
+// guaranteed to run in constant time; an Exponentiator backed by a constant
+// time implementation removes that source of timing variation. Blinding is
+// applied around it as usual.
+type Exponentiator interface {
+	// Exp returns x**y mod m. It must not modify its arguments.
+	Exp(x, y, m *big.Int) *big.Int
+}
+
+// WithExponentiator makes signing use e for the exponentiations with the
+// private exponent or its CRT parts. Public key operations keep using
+// math/big, as the public exponent is not secret.
+func WithExponentiator(e Exponentiator) Option {
+	return func(o *options) {
+		o.exponentiator = e
+	}
+}
+
+// exp computes x**y mod m with the Exponentiator of o, or with math/big.
+func (o *options) exp(x, y, m *big.Int) *big.Int {
+	if o.exponentiator != nil {
+		return o.exponentiator.Exp(x, y, m)
+	}
+	return new(big.Int).Exp(x, y, m)
+}
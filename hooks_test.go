@@ -0,0 +1,131 @@
+package pss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignCostHook(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("cost"))
+	var costs []SignCost
+	hook := WithSignCostHook(func(c SignCost) {
+		costs = append(costs, c)
+	})
+	_, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], []byte("salt"), hook)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(costs) != 1 {
+		t.Fatalf("hook called %d times, want 1", len(costs))
+	}
+	c := costs[0]
+	if c.ModulusBits != 2048 || !c.CRT {
+		t.Errorf("got %+v, want a 2048 bit CRT operation", c)
+	}
+	if c.Start.IsZero() || c.Duration() < 0 {
+		t.Errorf("bad timestamps: %v to %v", c.Start, c.End)
+	}
+}
+
+type recordingTracer struct {
+	events []string
+}
+
+func (r *recordingTracer) StartPhase(p SignPhase) {
+	r.events = append(r.events, "start "+p.String())
+}
+
+func (r *recordingTracer) EndPhase(p SignPhase) {
+	r.events = append(r.events, "end "+p.String())
+}
+
+func TestTracer(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("trace"))
+	tests := []struct {
+		opts []Option
+		want string
+	}{
+		{nil, "start hash check,end hash check,start encode,end encode," +
+			"start blinding,end blinding,start exponentiation,end exponentiation," +
+			"start padding,end padding"},
+		{[]Option{WithoutBlinding()}, "start hash check,end hash check,start encode,end encode," +
+			"start exponentiation,end exponentiation,start padding,end padding"},
+	}
+	for _, tt := range tests {
+		tr := new(recordingTracer)
+		if _, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], nil, append(tt.opts, WithTracer(tr))...); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Join(tr.events, ","); got != tt.want {
+			t.Errorf("got events %s\nwant %s", got, tt.want)
+		}
+	}
+
+	tr := new(recordingTracer)
+	SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:5], nil, WithTracer(tr))
+	if got, want := strings.Join(tr.events, ","), "start hash check,end hash check"; got != want {
+		t.Errorf("bad input: got events %s, want %s", got, want)
+	}
+}
+
+// countingExponentiator counts its calls and computes with math/big.
+type countingExponentiator struct {
+	calls int
+}
+
+func (e *countingExponentiator) Exp(x, y, m *big.Int) *big.Int {
+	e.calls++
+	return new(big.Int).Exp(x, y, m)
+}
+
+func TestWithExponentiator(t *testing.T) {
+	hashed := sha256.Sum256([]byte("exponentiator"))
+	salt := []byte("salt")
+	for _, test := range []struct {
+		name  string
+		priv  *rsa.PrivateKey
+		calls int
+	}{
+		{"CRT", testKey(t), 2},
+		{"no CRT", selfTestKey(), 1},
+	} {
+		e := new(countingExponentiator)
+		sig, err := SignPSS(rand.Reader, test.priv, crypto.SHA256, hashed[:], salt, WithExponentiator(e))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.calls != test.calls {
+			t.Errorf("%s: %d exponentiations, want %d", test.name, e.calls, test.calls)
+		}
+		want, err := SignPSS(rand.Reader, test.priv, crypto.SHA256, hashed[:], salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sig, want) {
+			t.Errorf("%s: signature differs from the default exponentiation", test.name)
+		}
+	}
+}
+
+func TestMeasureSignThroughput(t *testing.T) {
+	priv := testKey(t)
+	ops, err := MeasureSignThroughput(rand.Reader, priv, crypto.SHA256, 50*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ops <= 0 {
+		t.Errorf("got %v signatures per second", ops)
+	}
+	if _, err := MeasureSignThroughput(errReader{}, priv, crypto.SHA256, time.Millisecond); err == nil {
+		t.Errorf("failing random source: no error")
+	}
+}
@@ -0,0 +1,412 @@
+package pss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// CanonicalJSON returns the JSON Canonicalization Scheme (RFC 8785)
+// serialization of v: v is first marshaled with encoding/json, so struct
+// tags and json.Marshaler apply and a json.RawMessage is canonicalized as
+// it is, and the result is then written without white space, with object
+// members sorted by the UTF-16 code units of their names, strings escaped
+// as little as JSON allows and numbers in the shortest form that
+// ECMAScript gives an IEEE 754 double. Numbers are therefore limited to the
+// precision of a float64, as RFC 8785 requires; an integer beyond 2^53
+// should be sent as a string. As RFC 8785 requires of its I-JSON input, an
+// object with two members of the same name, a string with an unpaired
+// surrogate escape and invalid UTF-8 are rejected rather than read the way
+// one particular parser happens to.
+func CanonicalJSON(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	p := &jcsParser{data: data}
+	var buf bytes.Buffer
+	if err := p.value(&buf); err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos != len(p.data) {
+		return nil, p.errorf("data after the value")
+	}
+	return buf.Bytes(), nil
+}
+
+// jcsParser reads JSON from data and writes it in canonical form, token by
+// token, so that it sees duplicate names and escapes that a decoder into
+// Go values would quietly resolve.
+type jcsParser struct {
+	data []byte
+	pos  int
+}
+
+func (p *jcsParser) errorf(format string, v ...interface{}) error {
+	return fmt.Errorf("crypto/rsa: invalid JSON at offset %d: %s", p.pos, fmt.Sprintf(format, v...))
+}
+
+func (p *jcsParser) skipSpace() {
+	for p.pos < len(p.data) && strings.IndexByte(" \t\n\r", p.data[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// value writes the canonical form of the value at p.pos.
+func (p *jcsParser) value(buf *bytes.Buffer) error {
+	p.skipSpace()
+	if p.pos == len(p.data) {
+		return p.errorf("unexpected end of input")
+	}
+	switch c := p.data[p.pos]; {
+	case c == '{':
+		return p.object(buf)
+	case c == '[':
+		return p.array(buf)
+	case c == '"':
+		s, err := p.string()
+		if err != nil {
+			return err
+		}
+		writeCanonicalString(buf, s)
+	case c == '-' || '0' <= c && c <= '9':
+		start := p.pos
+		for p.pos < len(p.data) && strings.IndexByte("+-.0123456789eE", p.data[p.pos]) >= 0 {
+			p.pos++
+		}
+		num := string(p.data[start:p.pos])
+		f, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return fmt.Errorf("crypto/rsa: JSON number %s is not an IEEE 754 double", num)
+		}
+		buf.WriteString(formatJSNumber(f))
+	default:
+		for _, literal := range []string{"true", "false", "null"} {
+			if bytes.HasPrefix(p.data[p.pos:], []byte(literal)) {
+				p.pos += len(literal)
+				buf.WriteString(literal)
+				return nil
+			}
+		}
+		return p.errorf("unexpected character %q", c)
+	}
+	return nil
+}
+
+// object writes the object at p.pos with its members sorted.
+func (p *jcsParser) object(buf *bytes.Buffer) error {
+	type member struct {
+		name  string
+		value []byte
+	}
+	var members []member
+	seen := make(map[string]bool)
+	p.pos++
+	for first := true; ; first = false {
+		p.skipSpace()
+		if p.pos < len(p.data) && p.data[p.pos] == '}' && first {
+			p.pos++
+			break
+		}
+		if p.pos == len(p.data) || p.data[p.pos] != '"' {
+			return p.errorf("expected a member name")
+		}
+		name, err := p.string()
+		if err != nil {
+			return err
+		}
+		if seen[name] {
+			return p.errorf("duplicate member name %q", name)
+		}
+		seen[name] = true
+		if err := p.expect(':'); err != nil {
+			return err
+		}
+		var value bytes.Buffer
+		if err := p.value(&value); err != nil {
+			return err
+		}
+		members = append(members, member{name, value.Bytes()})
+		if done, err := p.next('}'); err != nil {
+			return err
+		} else if done {
+			break
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return lessUTF16(members[i].name, members[j].name) })
+	buf.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeCanonicalString(buf, m.name)
+		buf.WriteByte(':')
+		buf.Write(m.value)
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// array writes the array at p.pos.
+func (p *jcsParser) array(buf *bytes.Buffer) error {
+	p.pos++
+	buf.WriteByte('[')
+	for first := true; ; first = false {
+		p.skipSpace()
+		if p.pos < len(p.data) && p.data[p.pos] == ']' && first {
+			p.pos++
+			break
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		if err := p.value(buf); err != nil {
+			return err
+		}
+		if done, err := p.next(']'); err != nil {
+			return err
+		} else if done {
+			break
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+
+// expect consumes c, after any white space.
+func (p *jcsParser) expect(c byte) error {
+	p.skipSpace()
+	if p.pos == len(p.data) || p.data[p.pos] != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+// next consumes the comma before another element, reporting false, or the
+// closing delimiter end, reporting true.
+func (p *jcsParser) next(end byte) (done bool, err error) {
+	p.skipSpace()
+	if p.pos < len(p.data) {
+		switch p.data[p.pos] {
+		case ',':
+			p.pos++
+			return false, nil
+		case end:
+			p.pos++
+			return true, nil
+		}
+	}
+	return false, p.errorf("expected ',' or %q", end)
+}
+
+// string decodes the string at p.pos. It rejects invalid UTF-8 and \u
+// escapes of surrogates that do not form a pair.
+func (p *jcsParser) string() (string, error) {
+	p.pos++
+	var sb strings.Builder
+	for {
+		if p.pos == len(p.data) {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.data[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			return sb.String(), nil
+		case c == '\\':
+			if err := p.escape(&sb); err != nil {
+				return "", err
+			}
+		case c < 0x20:
+			return "", p.errorf("control character in string")
+		default:
+			r, size := utf8.DecodeRune(p.data[p.pos:])
+			if r == utf8.RuneError && size == 1 {
+				return "", p.errorf("invalid UTF-8 in string")
+			}
+			sb.WriteRune(r)
+			p.pos += size
+		}
+	}
+}
+
+// jsonEscapes maps the character after a backslash to the one it stands
+// for, for every escape but \u.
+var jsonEscapes = map[byte]rune{'"': '"', '\\': '\\', '/': '/', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t'}
+
+// escape decodes the escape sequence at p.pos.
+func (p *jcsParser) escape(sb *strings.Builder) error {
+	if p.pos+1 == len(p.data) {
+		return p.errorf("unterminated escape")
+	}
+	c := p.data[p.pos+1]
+	if c != 'u' {
+		r, ok := jsonEscapes[c]
+		if !ok {
+			return p.errorf("invalid escape \\%c", c)
+		}
+		sb.WriteRune(r)
+		p.pos += 2
+		return nil
+	}
+	r, err := p.hex4()
+	if err != nil {
+		return err
+	}
+	switch {
+	case utf16.IsSurrogate(r) && r < 0xDC00:
+		// A high surrogate must be followed by an escaped low one.
+		low, err := p.hex4()
+		if err != nil || low < 0xDC00 || low > 0xDFFF {
+			return p.errorf("unpaired surrogate \\u%04x", r)
+		}
+		r = utf16.DecodeRune(r, low)
+	case utf16.IsSurrogate(r):
+		return p.errorf("unpaired surrogate \\u%04x", r)
+	}
+	sb.WriteRune(r)
+	return nil
+}
+
+// hex4 decodes a \uXXXX escape at p.pos.
+func (p *jcsParser) hex4() (rune, error) {
+	if p.pos+6 > len(p.data) || p.data[p.pos] != '\\' || p.data[p.pos+1] != 'u' {
+		return 0, p.errorf("expected a \\u escape")
+	}
+	v, err := strconv.ParseUint(string(p.data[p.pos+2:p.pos+6]), 16, 16)
+	if err != nil {
+		return 0, p.errorf("invalid \\u escape")
+	}
+	p.pos += 6
+	return rune(v), nil
+}
+
+// lessUTF16 reports whether a sorts before b when both are compared as
+// sequences of UTF-16 code units, which differs from comparing their UTF-8
+// bytes for characters outside the Basic Multilingual Plane.
+func lessUTF16(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
+
+// writeCanonicalString writes s as a JSON string, escaping only the
+// quotation mark, the backslash and the control characters, the latter as
+// \b, \t, \n, \f, \r or \u00xx with lower case hex digits.
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\r':
+			buf.WriteString(`\r`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// formatJSNumber formats f as ECMAScript's Number.prototype.toString does:
+// the shortest digits that read back as f, in plain notation for
+// magnitudes from 1e-6 up to but excluding 1e21 and in exponent notation
+// otherwise. f must be finite.
+func formatJSNumber(f float64) string {
+	if f == 0 {
+		// Both zeros are written as 0.
+		return "0"
+	}
+	sign := ""
+	if f < 0 {
+		sign = "-"
+		f = -f
+	}
+	// FormatFloat gives d.ddde±x with the shortest digits.
+	mantissa, exp, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, 64), "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	x, _ := strconv.Atoi(exp)
+	// f = 0.digits × 10^n in the terms of the ECMAScript specification.
+	k, n := len(digits), x+1
+	switch {
+	case k <= n && n <= 21:
+		return sign + digits + strings.Repeat("0", n-k)
+	case 0 < n && n <= 21:
+		return sign + digits[:n] + "." + digits[n:]
+	case -6 < n && n <= 0:
+		return sign + "0." + strings.Repeat("0", -n) + digits
+	}
+	e := "e+" + strconv.Itoa(n-1)
+	if n-1 < 0 {
+		e = "e-" + strconv.Itoa(1-n)
+	}
+	if k == 1 {
+		return sign + digits + e
+	}
+	return sign + digits[:1] + "." + digits[1:] + e
+}
+
+// SignJSON signs the JSON value v, canonicalized with CanonicalJSON and
+// hashed with hash, so that a verifier that serializes the same value in
+// another way still computes the same digest. salt is used as in SignPSS.
+func SignJSON(rand io.Reader, priv *rsa.PrivateKey, hash crypto.Hash, v interface{}, salt []byte, opts ...Option) ([]byte, error) {
+	hashed, err := jsonDigest(hash, v)
+	if err != nil {
+		return nil, err
+	}
+	return SignPSS(rand, priv, hash, hashed, salt, opts...)
+}
+
+// VerifyJSON verifies sig as a signature of the JSON value v made by
+// SignJSON.
+func VerifyJSON(pub *rsa.PublicKey, hash crypto.Hash, v interface{}, sig []byte, sLen int, opts ...Option) error {
+	if !hash.Available() {
+		return rsa.ErrVerification
+	}
+	hashed, err := jsonDigest(hash, v)
+	if err != nil {
+		return err
+	}
+	return VerifyPSS(pub, hash, hashed, sig, sLen, opts...)
+}
+
+// jsonDigest returns the hash of the canonical JSON serialization of v.
+func jsonDigest(hash crypto.Hash, v interface{}) ([]byte, error) {
+	if !hash.Available() {
+		return nil, fmt.Errorf("crypto/rsa: hash function %v not available", hash)
+	}
+	data, err := CanonicalJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	h := hash.New()
+	h.Write(data)
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,157 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		v    interface{}
+		want string
+	}{
+		// The example of RFC 8785, Section 3.2.2.
+		{"RFC 8785", json.RawMessage(`{
+			"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+			"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+			"literals": [null, true, false]
+		}`), `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`},
+		{"map order", map[string]interface{}{"b": 2, "a": 1, "c": map[string]int{"z": 26, "y": 25}}, `{"a":1,"b":2,"c":{"y":25,"z":26}}`},
+		// U+1F600 is D83D DE00 in UTF-16 and sorts before U+FB33, unlike in
+		// UTF-8; RFC 8785 names this case explicitly.
+		{"UTF-16 order", map[string]string{"\U0001F600": "emoji", "\uFB33": "dalet", "\u00e9": "e acute", "\r": "cr"}, "{\"\\r\":\"cr\",\"\u00e9\":\"e acute\",\"\U0001F600\":\"emoji\",\"\uFB33\":\"dalet\"}"},
+		{"HTML", "<a href=\"x\">&</a>", `"<a href=\"x\">&</a>"`},
+		{"struct", struct {
+			Name  string `json:"name"`
+			Count int    `json:"count"`
+		}{"x", 3}, `{"count":3,"name":"x"}`},
+	} {
+		got, err := CanonicalJSON(test.v)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("%s: got %s, want %s", test.name, got, test.want)
+		}
+	}
+	if _, err := CanonicalJSON(json.RawMessage(`1e400`)); err == nil {
+		t.Errorf("number out of the range of a double accepted")
+	}
+}
+
+func TestCanonicalJSONStructure(t *testing.T) {
+	for _, test := range []struct {
+		in, want string
+	}{
+		{`{}`, `{}`},
+		{`[]`, `[]`},
+		{`[{},[],[1,[2]],{"a":{}}]`, `[{},[],[1,[2]],{"a":{}}]`},
+		{`{"a":{"x":1},"b":{"x":2}}`, `{"a":{"x":1},"b":{"x":2}}`},
+		{`"\ud83d\ude00\u00e9\/"`, "\"\U0001F600\u00e9/\""},
+		{`"<&>"`, `"<&>"`},
+	} {
+		got, err := CanonicalJSON(json.RawMessage(test.in))
+		if err != nil || string(got) != test.want {
+			t.Errorf("%s: got %s, %v; want %s", test.in, got, err, test.want)
+		}
+	}
+}
+
+func TestCanonicalJSONRejects(t *testing.T) {
+	for name, in := range map[string]string{
+		"duplicate name":        `{"amount":1,"amount":1000}`,
+		"nested duplicate":      `{"a":{"b":1,"b":2}}`,
+		"escaped duplicate":     `{"a":1,"\u0061":2}`,
+		"lone high surrogate":   `"\ud800"`,
+		"lone low surrogate":    `"\udc00"`,
+		"high then not low":     `"\ud800\u0041"`,
+		"high then two lows":    `"\ud800\udc00\udc00"`,
+		"surrogate in a name":   `{"\ud800":1}`,
+		"invalid UTF-8":         "\"\xff\"",
+		"invalid UTF-8 in name": "{\"\xc3\":1}",
+	} {
+		if got, err := CanonicalJSON(json.RawMessage(in)); err == nil {
+			t.Errorf("%s: canonicalized to %s", name, got)
+		}
+	}
+
+	// A signer whose parser kept the first value and a verifier whose
+	// parser keeps the last would disagree; neither may accept it.
+	priv := testKey(t)
+	sig, err := SignJSON(rand.Reader, priv, crypto.SHA256, map[string]int{"amount": 1}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyJSON(&priv.PublicKey, crypto.SHA256, json.RawMessage(`{"amount":1,"amount":1000}`), sig, 0); err == nil {
+		t.Errorf("document with a duplicate name verified")
+	}
+}
+
+func TestFormatJSNumber(t *testing.T) {
+	for _, test := range []struct {
+		f    float64
+		want string
+	}{
+		{0, "0"},
+		{math.Copysign(0, -1), "0"},
+		{1, "1"},
+		{-1.5, "-1.5"},
+		{100, "100"},
+		{123456789012345680000, "123456789012345680000"},
+		{1e21, "1e+21"},
+		{1e-6, "0.000001"},
+		{1e-7, "1e-7"},
+		{1.5e-7, "1.5e-7"},
+		{0.30000000000000004, "0.30000000000000004"},
+		{math.MaxFloat64, "1.7976931348623157e+308"},
+		{5e-324, "5e-324"},
+		{9007199254740993, "9007199254740992"},
+	} {
+		if got := formatJSNumber(test.f); got != test.want {
+			t.Errorf("formatJSNumber(%v) = %s, want %s", test.f, got, test.want)
+		}
+	}
+}
+
+func TestSignJSON(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	doc := map[string]interface{}{
+		"amount":   12.50,
+		"currency": "€",
+		"to":       map[string]interface{}{"name": "Zoë", "id": 7},
+	}
+	salt := []byte("json salt")
+	sig, err := SignJSON(rand.Reader, priv, crypto.SHA256, doc, salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The same document serialized another way verifies too.
+	reordered := json.RawMessage(`{ "to": {"id": 7.0, "name": "Zo\u00eb"}, "currency": "\u20ac", "amount": 1.25e1 }`)
+	for name, v := range map[string]interface{}{"map": doc, "reordered": reordered} {
+		if err := VerifyJSON(pub, crypto.SHA256, v, sig, len(salt)); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	doc["amount"] = 12.51
+	if err := VerifyJSON(pub, crypto.SHA256, doc, sig, len(salt)); err != rsa.ErrVerification {
+		t.Errorf("changed document: got %v, want rsa.ErrVerification", err)
+	}
+	if _, err := SignJSON(rand.Reader, priv, crypto.SHA256, func() {}, salt); err == nil {
+		t.Errorf("signed a value that is not JSON")
+	}
+	for _, hash := range []crypto.Hash{0, crypto.MD4, crypto.Hash(200)} {
+		if _, err := SignJSON(rand.Reader, priv, hash, doc, salt); err == nil {
+			t.Errorf("signed with unavailable hash %v", hash)
+		}
+		if err := VerifyJSON(pub, hash, doc, sig, len(salt)); err != rsa.ErrVerification {
+			t.Errorf("verifying with unavailable hash %v: got %v, want rsa.ErrVerification", hash, err)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha1"
+	"testing"
+)
+
+func TestSignPSSFixed(t *testing.T) {
+	key := selfTestKey()
+	hashed := sha1.Sum(mustDecodeHex(selfTestMsg))
+	salt := mustDecodeHex(selfTestSalt)
+	sig, err := SignPSSFixed(key, crypto.SHA1, hashed[:], salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !compareBytes(sig, mustDecodeHex(selfTestSig)) {
+		t.Errorf("signature does not match the known answer")
+	}
+	blinded, err := SignPSS(rand.Reader, key, crypto.SHA1, hashed[:], salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !compareBytes(sig, blinded) {
+		t.Errorf("SignPSSFixed and SignPSS disagree")
+	}
+}
+
+func TestSelfTest(t *testing.T) {
+	if err := SelfTest(); err != nil {
+		t.Error(err)
+	}
+}
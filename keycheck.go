@@ -0,0 +1,66 @@
+package pss
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// primalityRounds is the number of Miller-Rabin rounds used by
+// CheckKeyHealth, on top of the Baillie-PSW test of big.Int.ProbablyPrime.
+const primalityRounds = 20
+
+// CheckKeyHealth checks that the primes of priv are pairwise distinct, that
+// each is probably prime and that their product is the modulus. It guards
+// against corrupted or crafted keys and is meant to be run once, for
+// example when a signing daemon loads its key, since the primality tests
+// are expensive.
+func CheckKeyHealth(priv *rsa.PrivateKey) error {
+	if priv.N == nil {
+		return errors.New("crypto/rsa: key has no modulus")
+	}
+	if len(priv.Primes) < 2 {
+		return fmt.Errorf("crypto/rsa: key has %d primes, want at least 2", len(priv.Primes))
+	}
+	product := new(big.Int).Set(bigOne)
+	for i, p := range priv.Primes {
+		if p == nil || p.Cmp(bigOne) <= 0 {
+			return fmt.Errorf("crypto/rsa: prime %d is not greater than one", i)
+		}
+		for j := 0; j < i; j++ {
+			if p.Cmp(priv.Primes[j]) == 0 {
+				return fmt.Errorf("crypto/rsa: primes %d and %d are equal", j, i)
+			}
+		}
+		if !p.ProbablyPrime(primalityRounds) {
+			return fmt.Errorf("crypto/rsa: prime %d is composite", i)
+		}
+		product.Mul(product, p)
+	}
+	if product.Cmp(priv.N) != 0 {
+		return errors.New("crypto/rsa: product of primes is not the modulus")
+	}
+	return nil
+}
+
+// ValidatePublicKey checks that pub is usable for verification: the modulus
+// is odd, greater than one and within the limits of WithMaxModulusBits and
+// WithMinModulusBits, and the public exponent is odd and greater than one.
+// It is cheap and meant for keys received from a peer, before they are
+// used.
+func ValidatePublicKey(pub *rsa.PublicKey, opts ...Option) error {
+	if pub.N == nil || pub.N.Cmp(bigOne) <= 0 {
+		return errors.New("crypto/rsa: modulus is not greater than one")
+	}
+	if pub.N.Bit(0) == 0 {
+		return errors.New("crypto/rsa: modulus is even")
+	}
+	if err := newOptions(opts).checkModulusSize(pub.N); err != nil {
+		return err
+	}
+	if pub.E <= 1 || pub.E%2 == 0 {
+		return fmt.Errorf("crypto/rsa: public exponent %d is not odd and greater than one", pub.E)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package pss
+
+import (
+	"crypto/rsa"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestCheckKeyHealth(t *testing.T) {
+	priv := testKey(t)
+	if err := CheckKeyHealth(priv); err != nil {
+		t.Fatalf("generated key: %v", err)
+	}
+
+	p, q := priv.Primes[0], priv.Primes[1]
+	withPrimes := func(n *big.Int, primes ...*big.Int) *rsa.PrivateKey {
+		return &rsa.PrivateKey{PublicKey: rsa.PublicKey{N: n, E: priv.E}, D: priv.D, Primes: primes}
+	}
+	square := new(big.Int).Mul(p, p)
+	composite := new(big.Int).Mul(q, big.NewInt(3))
+	tests := map[string]*rsa.PrivateKey{
+		"one prime":      withPrimes(priv.N, p),
+		"repeated prime": withPrimes(square, p, p),
+		"composite":      withPrimes(new(big.Int).Mul(p, composite), p, composite),
+		"wrong modulus":  withPrimes(new(big.Int).Add(priv.N, bigOne), p, q),
+	}
+	for name, key := range tests {
+		if err := CheckKeyHealth(key); err == nil {
+			t.Errorf("%s: key accepted", name)
+		}
+	}
+}
+
+func TestValidatePublicKey(t *testing.T) {
+	pub := &testKey(t).PublicKey
+	if err := ValidatePublicKey(pub); err != nil {
+		t.Fatalf("generated key: %v", err)
+	}
+	if err := ValidatePublicKey(pub, WithMaxModulusBits(1024)); !errors.Is(err, ErrModulusTooLarge) {
+		t.Errorf("2048 bit key with a limit of 1024: got %v, want ErrModulusTooLarge", err)
+	}
+	huge := new(big.Int).Lsh(bigOne, DefaultMaxModulusBits)
+	huge.Add(huge, bigOne)
+	for _, test := range []struct {
+		name string
+		pub  *rsa.PublicKey
+	}{
+		{"nil modulus", &rsa.PublicKey{E: 65537}},
+		{"modulus one", &rsa.PublicKey{N: big.NewInt(1), E: 65537}},
+		{"even modulus", &rsa.PublicKey{N: new(big.Int).Add(pub.N, bigOne), E: 65537}},
+		{"modulus too large", &rsa.PublicKey{N: huge, E: 65537}},
+		{"exponent one", &rsa.PublicKey{N: pub.N, E: 1}},
+		{"even exponent", &rsa.PublicKey{N: pub.N, E: 65536}},
+		{"negative exponent", &rsa.PublicKey{N: pub.N, E: -3}},
+	} {
+		if err := ValidatePublicKey(test.pub); err == nil {
+			t.Errorf("%s: key accepted", test.name)
+		}
+	}
+}
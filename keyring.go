@@ -0,0 +1,192 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ErrUnknownKeyID is the cause of the errors that KeyRing and VerifierSet
+// return for a key id that they do not hold.
+var ErrUnknownKeyID = errors.New("crypto/rsa: unknown key id")
+
+func unknownKeyID(id string) error {
+	return fmt.Errorf("%w %q", ErrUnknownKeyID, id)
+}
+
+// A KeyRing holds several signing keys by id, one of which is current.
+// Signatures are made with the current key and carry its id, so that a
+// verifier can find the key again after the current one has changed. A
+// KeyRing is safe for concurrent use.
+type KeyRing struct {
+	mu      sync.RWMutex
+	keys    map[string]*rsa.PrivateKey
+	current string
+}
+
+// NewKeyRing returns an empty KeyRing.
+func NewKeyRing() *KeyRing {
+	return &KeyRing{keys: make(map[string]*rsa.PrivateKey)}
+}
+
+// Add adds priv under id. The first key added becomes the current one.
+func (r *KeyRing) Add(id string, priv *rsa.PrivateKey) error {
+	if priv == nil || priv.N == nil {
+		return fmt.Errorf("crypto/rsa: nil key for id %q", id)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.keys[id]; ok {
+		return fmt.Errorf("crypto/rsa: key id %q already in use", id)
+	}
+	r.keys[id] = priv
+	if len(r.keys) == 1 {
+		r.current = id
+	}
+	return nil
+}
+
+// SetCurrent makes the key with the given id the one that Sign uses.
+func (r *KeyRing) SetCurrent(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.keys[id]; !ok {
+		return unknownKeyID(id)
+	}
+	r.current = id
+	return nil
+}
+
+// Remove removes the key with the given id, for example once no signature
+// made with it needs to be verified any more. The current key cannot be
+// removed.
+func (r *KeyRing) Remove(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.keys[id]; !ok {
+		return unknownKeyID(id)
+	}
+	if id == r.current {
+		return fmt.Errorf("crypto/rsa: cannot remove current key %q", id)
+	}
+	delete(r.keys, id)
+	return nil
+}
+
+// PublicKey returns the public key with the given id.
+func (r *KeyRing) PublicKey(id string) (*rsa.PublicKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	priv, ok := r.keys[id]
+	if !ok {
+		return nil, unknownKeyID(id)
+	}
+	return &priv.PublicKey, nil
+}
+
+// Sign signs hashed like SignPSSAutoSalt with the current key and returns
+// the signature and the id of the key.
+func (r *KeyRing) Sign(rand io.Reader, hash crypto.Hash, hashed []byte, saltLen int, opts ...Option) (sig []byte, keyID string, err error) {
+	r.mu.RLock()
+	keyID = r.current
+	priv, ok := r.keys[keyID]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, "", errors.New("crypto/rsa: key ring is empty")
+	}
+	sig, err = SignPSSAutoSalt(rand, priv, hash, hashed, saltLen, opts...)
+	if err != nil {
+		return nil, "", err
+	}
+	return sig, keyID, nil
+}
+
+// Verify verifies sig like VerifyPSS with the key with the given id.
+func (r *KeyRing) Verify(keyID string, hash crypto.Hash, hashed []byte, sig []byte, sLen int, opts ...Option) error {
+	pub, err := r.PublicKey(keyID)
+	if err != nil {
+		return err
+	}
+	return VerifyPSS(pub, hash, hashed, sig, sLen, opts...)
+}
+
+// A VerifierSet holds the public keys of several signers by id, as a JWKS
+// does, and verifies each signature with the key its id names. A
+// VerifierSet is safe for concurrent use.
+type VerifierSet struct {
+	mu   sync.RWMutex
+	keys map[string]*rsa.PublicKey
+}
+
+// NewVerifierSet returns an empty VerifierSet.
+func NewVerifierSet() *VerifierSet {
+	return &VerifierSet{keys: make(map[string]*rsa.PublicKey)}
+}
+
+// Add adds pub under id.
+func (s *VerifierSet) Add(id string, pub *rsa.PublicKey) error {
+	if pub == nil || pub.N == nil {
+		return fmt.Errorf("crypto/rsa: nil key for id %q", id)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.keys[id]; ok {
+		return fmt.Errorf("crypto/rsa: key id %q already in use", id)
+	}
+	s.keys[id] = pub
+	return nil
+}
+
+// Remove removes the key with the given id.
+func (s *VerifierSet) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.keys[id]; !ok {
+		return unknownKeyID(id)
+	}
+	delete(s.keys, id)
+	return nil
+}
+
+// Verify verifies sig like VerifyPSS with the key with the given id. An
+// unknown id gives an error that matches ErrUnknownKeyID.
+func (s *VerifierSet) Verify(keyID string, hash crypto.Hash, hashed []byte, sig []byte, sLen int, opts ...Option) error {
+	s.mu.RLock()
+	pub, ok := s.keys[keyID]
+	s.mu.RUnlock()
+	if !ok {
+		return unknownKeyID(keyID)
+	}
+	return VerifyPSS(pub, hash, hashed, sig, sLen, opts...)
+}
+
+// VerifyPSSAny verifies sig against each of pubs in turn, for a verifier
+// that does not know which of several keys made it, such as during a key
+// rotation while signatures by the old and the new key are both in use. It
+// returns the index of the first key under which sig is valid, without
+// trying the remaining ones. A nil entry fails like a key that does not
+// verify. If none verifies it returns -1 and an error that joins the
+// failure of every key.
+func VerifyPSSAny(pubs []*rsa.PublicKey, hash crypto.Hash, hashed []byte, sig []byte, sLen int, opts ...Option) (int, error) {
+	if len(pubs) == 0 {
+		return -1, errors.New("crypto/rsa: no keys to verify with")
+	}
+	o := newOptions(opts)
+	sc := new(verifyScratch)
+	var errs []error
+	for i, pub := range pubs {
+		if pub == nil || pub.N == nil {
+			errs = append(errs, fmt.Errorf("crypto/rsa: key %d is nil", i))
+			continue
+		}
+		err := verifyPSS(pub, hash.New, hashed, sig, sLen, sc, o)
+		if err == nil {
+			return i, nil
+		}
+		errs = append(errs, fmt.Errorf("key %d: %w", i, err))
+	}
+	return -1, errors.Join(errs...)
+}
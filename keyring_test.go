@@ -0,0 +1,168 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestKeyRing(t *testing.T) {
+	r := NewKeyRing()
+	hashed := sha256.Sum256([]byte("key ring"))
+	if _, _, err := r.Sign(rand.Reader, crypto.SHA256, hashed[:], sha256.Size); err == nil {
+		t.Errorf("signing with an empty key ring succeeded")
+	}
+	old := testKey(t)
+	next, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add("2025-01", old); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add("2026-01", next); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add("2026-01", next); err == nil {
+		t.Errorf("duplicate key id accepted")
+	}
+	if err := r.Add("nil", nil); err == nil {
+		t.Errorf("nil key accepted")
+	}
+	if err := r.Add("empty", new(rsa.PrivateKey)); err == nil {
+		t.Errorf("key without a modulus accepted")
+	}
+
+	sig, id, err := r.Sign(rand.Reader, crypto.SHA256, hashed[:], sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "2025-01" {
+		t.Errorf("signed with %q, want the first key", id)
+	}
+	if err := r.SetCurrent("2026-01"); err != nil {
+		t.Fatal(err)
+	}
+	sig2, id2, err := r.Sign(rand.Reader, crypto.SHA256, hashed[:], sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id2 != "2026-01" {
+		t.Errorf("signed with %q after rotation", id2)
+	}
+	for _, s := range []struct {
+		id  string
+		sig []byte
+	}{{id, sig}, {id2, sig2}} {
+		if err := r.Verify(s.id, crypto.SHA256, hashed[:], s.sig, sha256.Size); err != nil {
+			t.Errorf("%s: %v", s.id, err)
+		}
+	}
+	if err := r.Verify(id2, crypto.SHA256, hashed[:], sig, sha256.Size); err == nil {
+		t.Errorf("signature verified with the other key")
+	}
+
+	if err := r.Remove("2026-01"); err == nil {
+		t.Errorf("current key removed")
+	}
+	if err := r.Remove("2025-01"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Verify(id, crypto.SHA256, hashed[:], sig, sha256.Size); !errors.Is(err, ErrUnknownKeyID) {
+		t.Errorf("removed key: got %v, want ErrUnknownKeyID", err)
+	}
+	if err := r.SetCurrent("nonexistent"); !errors.Is(err, ErrUnknownKeyID) {
+		t.Errorf("unknown key: got %v, want ErrUnknownKeyID", err)
+	}
+}
+
+func TestVerifierSet(t *testing.T) {
+	a := testKey(t)
+	b, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewVerifierSet()
+	if err := s.Add("a", &a.PublicKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add("b", &b.PublicKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add("b", &a.PublicKey); err == nil {
+		t.Errorf("duplicate key id accepted")
+	}
+	if err := s.Add("nil", nil); err == nil {
+		t.Errorf("nil key accepted")
+	}
+	if err := s.Add("empty", new(rsa.PublicKey)); err == nil {
+		t.Errorf("key without a modulus accepted")
+	}
+	hashed := sha256.Sum256([]byte("verifier set"))
+	sig, err := SignPSSAutoSalt(rand.Reader, b, crypto.SHA256, hashed[:], sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Verify("b", crypto.SHA256, hashed[:], sig, sha256.Size); err != nil {
+		t.Errorf("right key: %v", err)
+	}
+	if err := s.Verify("a", crypto.SHA256, hashed[:], sig, sha256.Size); err == nil {
+		t.Errorf("wrong key accepted the signature")
+	}
+	err = s.Verify("c", crypto.SHA256, hashed[:], sig, sha256.Size)
+	if !errors.Is(err, ErrUnknownKeyID) || !strings.Contains(err.Error(), `"c"`) {
+		t.Errorf("unknown key: got %v, want ErrUnknownKeyID naming the id", err)
+	}
+	if err := s.Remove("b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Verify("b", crypto.SHA256, hashed[:], sig, sha256.Size); !errors.Is(err, ErrUnknownKeyID) {
+		t.Errorf("removed key: got %v, want ErrUnknownKeyID", err)
+	}
+}
+
+func TestVerifyPSSAny(t *testing.T) {
+	var privs []*rsa.PrivateKey
+	var pubs []*rsa.PublicKey
+	for i := 0; i < 3; i++ {
+		priv, err := rsa.GenerateKey(rand.Reader, 1024)
+		if err != nil {
+			t.Fatal(err)
+		}
+		privs = append(privs, priv)
+		pubs = append(pubs, &priv.PublicKey)
+	}
+	hashed := sha256.Sum256([]byte("rotation"))
+	for i, priv := range privs {
+		sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], 32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := VerifyPSSAny(pubs, crypto.SHA256, hashed[:], sig, 32)
+		if err != nil || got != i {
+			t.Errorf("signature by key %d: got %d, %v", i, got, err)
+		}
+		if got, err := VerifyPSSAny(pubs[:i], crypto.SHA256, hashed[:], sig, 32); (i > 0 && !errors.Is(err, rsa.ErrVerification)) || got != -1 {
+			t.Errorf("signature by key %d against the others: got %d, %v", i, got, err)
+		}
+	}
+	if _, err := VerifyPSSAny(nil, crypto.SHA256, hashed[:], nil, 32); err == nil {
+		t.Errorf("verifying with no keys succeeded")
+	}
+
+	sig, err := SignPSSAutoSalt(rand.Reader, privs[1], crypto.SHA256, hashed[:], 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withNil := []*rsa.PublicKey{nil, new(rsa.PublicKey), pubs[1]}
+	if got, err := VerifyPSSAny(withNil, crypto.SHA256, hashed[:], sig, 32); err != nil || got != 2 {
+		t.Errorf("after nil keys: got %d, %v", got, err)
+	}
+	if got, err := VerifyPSSAny(withNil[:2], crypto.SHA256, hashed[:], sig, 32); err == nil || got != -1 {
+		t.Errorf("only nil keys: got %d, %v", got, err)
+	}
+}
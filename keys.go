@@ -0,0 +1,86 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// PublicKeyFromBytes returns the public key with the given modulus, a
+// big-endian unsigned integer such as the "n" member of a JWK after base64
+// decoding, and public exponent. Leading zero bytes of modulus are ignored.
+func PublicKeyFromBytes(modulus []byte, exponent int) *rsa.PublicKey {
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(modulus),
+		E: exponent,
+	}
+}
+
+// PublicKeyEqual reports whether a and b are the same key, that is whether
+// they have the same modulus and public exponent. The moduli are compared in
+// time that depends only on their lengths. Two nil keys are equal; a nil key
+// is not equal to a non-nil one.
+func PublicKeyEqual(a, b *rsa.PublicKey) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.N == nil || b.N == nil {
+		return a.N == b.N && a.E == b.E
+	}
+	return subtle.ConstantTimeCompare(a.N.Bytes(), b.N.Bytes()) == 1 && a.E == b.E
+}
+
+// KeyFingerprint returns the SHA-256 hash of the DER encoded
+// SubjectPublicKeyInfo of pub, which identifies the key independently of how
+// it is stored and can be used to pin it.
+func KeyFingerprint(pub *rsa.PublicKey) ([sha256.Size]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(der), nil
+}
+
+// GeneratePSSKey generates an RSA key of the given bit size for signing with
+// hash and a salt as long as the hash, the RecommendedSaltLength. It fails
+// without generating anything if such a signature does not fit a key of
+// that size, rather than leaving the error to the first signature. The key
+// is precomputed and can be shared between goroutines right away.
+func GeneratePSSKey(random io.Reader, bits int, hash crypto.Hash) (*rsa.PrivateKey, error) {
+	if err := IsValidPSSConfig(bits, hash, hash.Size()); err != nil {
+		return nil, err
+	}
+	priv, err := rsa.GenerateKey(random, bits)
+	if err != nil {
+		return nil, err
+	}
+	priv.Precompute()
+	return priv, nil
+}
+
+// IsValidPSSConfig returns nil if a modulus of modulusBits bits can hold a
+// PSS signature with hash and a salt of saltLen bytes and the default
+// trailer, that is if emLen >= hLen + sLen + 2 for emBits = modulusBits-1,
+// and an error that names the sizes otherwise. It does only arithmetic, so
+// a configuration can be checked at startup, before any key exists.
+func IsValidPSSConfig(modulusBits int, hash crypto.Hash, saltLen int) error {
+	if !hash.Available() {
+		return fmt.Errorf("crypto/rsa: hash function %v not available", hash)
+	}
+	if modulusBits <= 0 {
+		return fmt.Errorf("crypto/rsa: invalid modulus size of %d bits", modulusBits)
+	}
+	if saltLen < 0 {
+		return errNegativeSaltLength
+	}
+	emLen := (modulusBits - 1 + 7) / 8
+	if need := hash.Size() + saltLen + 2; emLen < need {
+		return fmt.Errorf("crypto/rsa: %d bit key too small for PSS with %v and a %d byte salt: need %d bytes of encoded message, have %d", modulusBits, hash, saltLen, need, emLen)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestPublicKeyFromBytes(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("key from bytes"))
+	sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, modulus := range [][]byte{priv.N.Bytes(), append([]byte{0}, priv.N.Bytes()...)} {
+		pub := PublicKeyFromBytes(modulus, priv.E)
+		if pub.N.Cmp(priv.N) != 0 || pub.E != priv.E {
+			t.Errorf("got N=%x E=%d, want N=%x E=%d", pub.N, pub.E, priv.N, priv.E)
+		}
+		if err := VerifyPSS(pub, crypto.SHA256, hashed[:], sig, sha256.Size); err != nil {
+			t.Errorf("verify with constructed key: %v", err)
+		}
+	}
+}
+
+func TestPublicKeyEqual(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	same := PublicKeyFromBytes(pub.N.Bytes(), pub.E)
+	otherN := &rsa.PublicKey{N: new(big.Int).Add(pub.N, big.NewInt(2)), E: pub.E}
+	otherE := &rsa.PublicKey{N: pub.N, E: 3}
+	tests := []struct {
+		name string
+		a, b *rsa.PublicKey
+		want bool
+	}{
+		{"same", pub, same, true},
+		{"modulus", pub, otherN, false},
+		{"exponent", pub, otherE, false},
+		{"nil", pub, nil, false},
+		{"both nil", nil, nil, true},
+		{"nil modulus", pub, &rsa.PublicKey{E: pub.E}, false},
+	}
+	for _, test := range tests {
+		if got := PublicKeyEqual(test.a, test.b); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+		if got := PublicKeyEqual(test.b, test.a); got != test.want {
+			t.Errorf("%s, swapped: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestKeyFingerprint(t *testing.T) {
+	priv := testKey(t)
+	fp, err := KeyFingerprint(&priv.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fp != sha256.Sum256(der) {
+		t.Errorf("fingerprint is not the hash of the SubjectPublicKeyInfo")
+	}
+	other, err := KeyFingerprint(&rsa.PublicKey{N: priv.N, E: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == fp {
+		t.Errorf("keys with different exponents have the same fingerprint")
+	}
+}
+
+func TestGeneratePSSKey(t *testing.T) {
+	priv, err := GeneratePSSKey(rand.Reader, 1024, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if priv.N.BitLen() != 1024 || priv.Precomputed.Dp == nil {
+		t.Errorf("got a %d bit key, precomputed: %v", priv.N.BitLen(), priv.Precomputed.Dp != nil)
+	}
+	hashed := sha256.Sum256([]byte("generated key"))
+	sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], RecommendedSaltLength(crypto.SHA256))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyPSS(&priv.PublicKey, crypto.SHA256, hashed[:], sig, sha256.Size); err != nil {
+		t.Error(err)
+	}
+
+	// SHA-512 with a 64 byte salt needs at least 130 bytes of encoded
+	// message, that is a modulus of at least 1034 bits.
+	if _, err := GeneratePSSKey(errReader{}, 1032, crypto.SHA512); err == nil || strings.Contains(err.Error(), "random") {
+		t.Errorf("1032 bit key for SHA-512: got %v, want a size error", err)
+	}
+}
+
+func TestIsValidPSSConfig(t *testing.T) {
+	for _, test := range []struct {
+		bits    int
+		hash    crypto.Hash
+		saltLen int
+		valid   bool
+	}{
+		{2048, crypto.SHA256, 32, true},
+		{2048, crypto.SHA256, 222, true},
+		{2048, crypto.SHA256, 223, false},
+		// SHA-512 with a 64 byte salt needs 130 bytes of encoded message.
+		{1034, crypto.SHA512, 64, true},
+		{1033, crypto.SHA512, 64, false},
+		{1033, crypto.SHA512, 63, true},
+		{2048, crypto.SHA256, -1, false},
+		{0, crypto.SHA256, 0, false},
+		{2048, crypto.Hash(0), 0, false},
+	} {
+		err := IsValidPSSConfig(test.bits, test.hash, test.saltLen)
+		if (err == nil) != test.valid {
+			t.Errorf("IsValidPSSConfig(%d, %v, %d) = %v, want valid = %v", test.bits, test.hash, test.saltLen, err, test.valid)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"encoding/binary"
+	"io"
+	"sort"
+)
+
+// manifestDigest returns the hash of the canonical encoding of manifest.
+func manifestDigest(hash crypto.Hash, manifest map[string][]byte) []byte {
+	names := make([]string, 0, len(manifest))
+	for name := range manifest {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := hash.New()
+	var n [8]byte
+	for _, name := range names {
+		binary.BigEndian.PutUint64(n[:], uint64(len(name)))
+		h.Write(n[:])
+		io.WriteString(h, name)
+		binary.BigEndian.PutUint64(n[:], uint64(len(manifest[name])))
+		h.Write(n[:])
+		h.Write(manifest[name])
+	}
+	return h.Sum(nil)
+}
+
+// SignManifest signs the canonical encoding of manifest, hashed with hash,
+// with a random salt of saltLen bytes. A manifest maps file names to the
+// hashes of the files, and its canonical encoding holds the entries sorted
+// by name, each written as
+//
+//	len(name) || name || len(hash) || hash
+//
+// with the lengths as 8 byte big-endian integers. The encoding is
+// unambiguous, so two different manifests never hash alike.
+func SignManifest(rand io.Reader, priv *rsa.PrivateKey, hash crypto.Hash, manifest map[string][]byte, saltLen int, opts ...Option) ([]byte, error) {
+	return SignPSSAutoSalt(rand, priv, hash, manifestDigest(hash, manifest), saltLen, opts...)
+}
+
+// VerifyManifest verifies sig as a signature of manifest made by
+// SignManifest. It only checks the manifest itself; the caller still has to
+// compare the hash of every file with its entry.
+func VerifyManifest(pub *rsa.PublicKey, hash crypto.Hash, manifest map[string][]byte, sig []byte, sLen int, opts ...Option) error {
+	return VerifyPSS(pub, hash, manifestDigest(hash, manifest), sig, sLen, opts...)
+}
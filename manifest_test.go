@@ -0,0 +1,56 @@
+package pss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestManifest(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	manifest := map[string][]byte{
+		"bin/tool":  bytes.Repeat([]byte{1}, sha256.Size),
+		"README":    bytes.Repeat([]byte{2}, sha256.Size),
+		"lib/a.so":  bytes.Repeat([]byte{3}, sha256.Size),
+		"empty.txt": nil,
+	}
+	sig, err := SignManifest(rand.Reader, priv, crypto.SHA256, manifest, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyManifest(pub, crypto.SHA256, manifest, sig, 32); err != nil {
+		t.Fatalf("valid manifest: %v", err)
+	}
+
+	copied := make(map[string][]byte)
+	for name, h := range manifest {
+		copied[name] = h
+	}
+	copied["lib/a.so"] = bytes.Repeat([]byte{4}, sha256.Size)
+	if err := VerifyManifest(pub, crypto.SHA256, copied, sig, 32); err != rsa.ErrVerification {
+		t.Errorf("changed entry: got %v, want rsa.ErrVerification", err)
+	}
+	delete(copied, "lib/a.so")
+	if err := VerifyManifest(pub, crypto.SHA256, copied, sig, 32); err != rsa.ErrVerification {
+		t.Errorf("removed entry: got %v, want rsa.ErrVerification", err)
+	}
+}
+
+func TestManifestDigestUnambiguous(t *testing.T) {
+	a := manifestDigest(crypto.SHA256, map[string][]byte{"ab": []byte("c")})
+	b := manifestDigest(crypto.SHA256, map[string][]byte{"a": []byte("bc")})
+	if bytes.Equal(a, b) {
+		t.Errorf("manifests differing in where name ends hash alike")
+	}
+
+	want := sha256.Sum256([]byte("\x00\x00\x00\x00\x00\x00\x00\x01a\x00\x00\x00\x00\x00\x00\x00\x01x" +
+		"\x00\x00\x00\x00\x00\x00\x00\x01b\x00\x00\x00\x00\x00\x00\x00\x00"))
+	got := manifestDigest(crypto.SHA256, map[string][]byte{"b": nil, "a": []byte("x")})
+	if !bytes.Equal(got, want[:]) {
+		t.Errorf("digest %x, want %x", got, want)
+	}
+}
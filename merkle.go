@@ -0,0 +1,66 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MerkleRoot returns the root of the Merkle tree over leaves, the hashes of
+// the data items in order, computed with hash. The tree is that of RFC 6962,
+// Section 2.1, with the leaf hashes as its inputs:
+//
+//	node(leaf)        = Hash(0x00 || leaf)
+//	node(left, right) = Hash(0x01 || left || right)
+//
+// where a list of n > 1 leaves is split after the largest power of two
+// smaller than n. The distinct prefixes keep a leaf from being taken for an
+// inner node. Every leaf must be hash.Size() bytes long.
+func MerkleRoot(hash crypto.Hash, leaves [][]byte) ([]byte, error) {
+	if len(leaves) == 0 {
+		return nil, errors.New("crypto/rsa: no leaves for the Merkle tree")
+	}
+	for i, leaf := range leaves {
+		if len(leaf) != hash.Size() {
+			return nil, fmt.Errorf("crypto/rsa: Merkle leaf %d is %d bytes, want %d", i, len(leaf), hash.Size())
+		}
+	}
+	return merkleNode(hash, leaves), nil
+}
+
+func merkleNode(hash crypto.Hash, leaves [][]byte) []byte {
+	h := hash.New()
+	if len(leaves) == 1 {
+		h.Write([]byte{0x00})
+		h.Write(leaves[0])
+		return h.Sum(nil)
+	}
+	k := 1
+	for k*2 < len(leaves) {
+		k *= 2
+	}
+	h.Write([]byte{0x01})
+	h.Write(merkleNode(hash, leaves[:k]))
+	h.Write(merkleNode(hash, leaves[k:]))
+	return h.Sum(nil)
+}
+
+// SignMerkleRoot signs the Merkle root of leaves, as computed by MerkleRoot,
+// with a random salt of saltLen bytes.
+func SignMerkleRoot(rand io.Reader, priv *rsa.PrivateKey, hash crypto.Hash, leaves [][]byte, saltLen int, opts ...Option) ([]byte, error) {
+	root, err := MerkleRoot(hash, leaves)
+	if err != nil {
+		return nil, err
+	}
+	return SignPSSAutoSalt(rand, priv, hash, root, saltLen, opts...)
+}
+
+// VerifyMerkleRoot verifies sig as a signature made by SignMerkleRoot over a
+// tree with the given root. The root is used as the digest, so this is
+// VerifyPSS under another name, kept so that signer and verifier are
+// visibly paired.
+func VerifyMerkleRoot(pub *rsa.PublicKey, hash crypto.Hash, root []byte, sig []byte, sLen int, opts ...Option) error {
+	return VerifyPSS(pub, hash, root, sig, sLen, opts...)
+}
@@ -0,0 +1,72 @@
+package pss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestMerkleRoot(t *testing.T) {
+	var leaves [][]byte
+	for i := 0; i < 3; i++ {
+		leaf := sha256.Sum256([]byte{byte(i)})
+		leaves = append(leaves, leaf[:])
+	}
+	node := func(prefix byte, parts ...[]byte) []byte {
+		h := sha256.New()
+		h.Write([]byte{prefix})
+		for _, p := range parts {
+			h.Write(p)
+		}
+		return h.Sum(nil)
+	}
+	l0, l1, l2 := node(0, leaves[0]), node(0, leaves[1]), node(0, leaves[2])
+	for _, test := range []struct {
+		n    int
+		want []byte
+	}{
+		{1, l0},
+		{2, node(1, l0, l1)},
+		{3, node(1, node(1, l0, l1), l2)},
+	} {
+		got, err := MerkleRoot(crypto.SHA256, leaves[:test.n])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, test.want) {
+			t.Errorf("%d leaves: root %x, want %x", test.n, got, test.want)
+		}
+	}
+
+	if _, err := MerkleRoot(crypto.SHA256, nil); err == nil {
+		t.Errorf("empty tree has a root")
+	}
+	if _, err := MerkleRoot(crypto.SHA256, [][]byte{leaves[0][:31]}); err == nil {
+		t.Errorf("short leaf accepted")
+	}
+}
+
+func TestSignMerkleRoot(t *testing.T) {
+	priv := testKey(t)
+	var leaves [][]byte
+	for i := 0; i < 5; i++ {
+		leaf := sha256.Sum256([]byte{byte(i)})
+		leaves = append(leaves, leaf[:])
+	}
+	sig, err := SignMerkleRoot(rand.Reader, priv, crypto.SHA256, leaves, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, _ := MerkleRoot(crypto.SHA256, leaves)
+	if err := VerifyMerkleRoot(&priv.PublicKey, crypto.SHA256, root, sig, 32); err != nil {
+		t.Errorf("valid root: %v", err)
+	}
+	leaves[0], leaves[1] = leaves[1], leaves[0]
+	root, _ = MerkleRoot(crypto.SHA256, leaves)
+	if err := VerifyMerkleRoot(&priv.PublicKey, crypto.SHA256, root, sig, 32); err != rsa.ErrVerification {
+		t.Errorf("reordered leaves: got %v, want rsa.ErrVerification", err)
+	}
+}
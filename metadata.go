@@ -0,0 +1,51 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"io"
+	"time"
+)
+
+// PSSMeta describes how a signature was made, for audit logs.
+//
+// The salt is not secret: it can be recovered from the signature by anyone
+// with the public key, and knowing it does not help to forge signatures, so
+// PSSMeta can be logged and stored like the signature itself. This is unlike
+// the private key, which must never be logged.
+type PSSMeta struct {
+	// Salt is the salt that was encoded into the signature.
+	Salt []byte
+
+	// Hash is the hash function of the signature.
+	Hash crypto.Hash
+
+	// SaltLength is the length of Salt in bytes, the sLen a verifier has
+	// to use.
+	SaltLength int
+
+	// Time is when the signature was made.
+	Time time.Time
+}
+
+// SignPSSWithMetadata is like SignPSSAutoSalt but also returns a description
+// of the signature, including the salt that was used, so that the caller can
+// record it. meta is only valid if err is nil.
+func SignPSSWithMetadata(rand io.Reader, priv *rsa.PrivateKey, hash crypto.Hash, hashed []byte, saltLen int, opts ...Option) (sig []byte, meta PSSMeta, err error) {
+	o := newOptions(opts)
+	salt, err := generateSalt(rand, hashed, saltLen, o)
+	if err != nil {
+		return nil, PSSMeta{}, err
+	}
+	sig, err = signPSS(rand, priv, hash, hashed, salt, o)
+	if err != nil {
+		return nil, PSSMeta{}, err
+	}
+	meta = PSSMeta{
+		Salt:       salt,
+		Hash:       hash,
+		SaltLength: len(salt),
+		Time:       time.Now(),
+	}
+	return sig, meta, nil
+}
@@ -0,0 +1,40 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+	"time"
+)
+
+func TestSignPSSWithMetadata(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("audit"))
+	before := time.Now()
+	sig, meta, err := SignPSSWithMetadata(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Hash != crypto.SHA256 || meta.SaltLength != sha256.Size || len(meta.Salt) != sha256.Size {
+		t.Errorf("got hash %v, salt length %d and %d salt bytes", meta.Hash, meta.SaltLength, len(meta.Salt))
+	}
+	if meta.Time.Before(before) || meta.Time.After(time.Now()) {
+		t.Errorf("timestamp %v outside of the call", meta.Time)
+	}
+	if err := VerifyPSS(&priv.PublicKey, meta.Hash, hashed[:], sig, meta.SaltLength); err != nil {
+		t.Errorf("verify with the recorded parameters: %v", err)
+	}
+	// Signing again with the recorded salt reproduces the signature.
+	again, err := SignPSS(rand.Reader, priv, meta.Hash, hashed[:], meta.Salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !compareBytes(sig, again) {
+		t.Errorf("signing with the recorded salt gave a different signature")
+	}
+
+	if _, _, err := SignPSSWithMetadata(errReader{}, priv, crypto.SHA256, hashed[:], sha256.Size); err == nil {
+		t.Errorf("signing with a failing random source succeeded")
+	}
+}
@@ -0,0 +1,60 @@
+package pss
+
+import (
+	"errors"
+	"hash"
+	"io"
+)
+
+// NewMGF1Reader returns a reader that yields the output of the MGF1 mask
+// generation function of PKCS #1 v2.1 for seed, using hash. The output is
+// produced lazily, one hash block at a time, so callers can read as much of
+// the mask as they need. MGF1 is defined for at most 2^32 blocks; the
+// reader returns io.EOF after that.
+//
+// The reader takes ownership of hash and resets it before every block.
+func NewMGF1Reader(hash hash.Hash, seed []byte) io.Reader {
+	return &mgf1Reader{
+		hash: hash,
+		seed: append([]byte(nil), seed...),
+	}
+}
+
+type mgf1Reader struct {
+	hash    hash.Hash
+	seed    []byte
+	counter [4]byte
+	blocks  uint64
+	block   []byte
+	off     int
+}
+
+const mgf1MaxBlocks = 1 << 32
+
+// errEmptyMGF1Block reports a hash whose Sum returned no bytes, with which
+// MGF1 would never make progress.
+var errEmptyMGF1Block = errors.New("crypto/rsa: MGF1 hash produced an empty digest")
+
+func (r *mgf1Reader) Read(p []byte) (n int, err error) {
+	for n < len(p) {
+		if r.off == len(r.block) {
+			if r.blocks == mgf1MaxBlocks {
+				return n, io.EOF
+			}
+			r.hash.Reset()
+			r.hash.Write(r.seed)
+			r.hash.Write(r.counter[0:4])
+			r.block = r.hash.Sum(r.block[:0])
+			if len(r.block) == 0 {
+				return n, errEmptyMGF1Block
+			}
+			r.off = 0
+			r.blocks++
+			incCounter(&r.counter)
+		}
+		c := copy(p[n:], r.block[r.off:])
+		n += c
+		r.off += c
+	}
+	return n, nil
+}
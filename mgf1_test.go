@@ -0,0 +1,193 @@
+package pss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"testing"
+)
+
+func TestMGF1Reader(t *testing.T) {
+	seed := []byte("mgf1 seed")
+	for _, n := range []int{0, 1, 31, 32, 33, 100, 1000} {
+		want := make([]byte, n)
+		mgf1XOR(want, sha256.New(), seed)
+
+		got := make([]byte, n)
+		if _, err := io.ReadFull(NewMGF1Reader(sha256.New(), seed), got); err != nil {
+			t.Fatalf("%d bytes: %v", n, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%d bytes: reader output differs from mgf1XOR", n)
+		}
+
+		// Reading in small, uneven pieces must give the same stream.
+		r := NewMGF1Reader(sha256.New(), seed)
+		var pieces []byte
+		buf := make([]byte, 7)
+		for len(pieces) < n {
+			m, _ := r.Read(buf[:1+len(pieces)%len(buf)])
+			pieces = append(pieces, buf[:m]...)
+		}
+		if !bytes.Equal(pieces[:n], want) {
+			t.Errorf("%d bytes: piecewise reads differ from mgf1XOR", n)
+		}
+	}
+}
+
+// plainHash hides the state marshaling methods of the hash it wraps.
+type plainHash struct {
+	hash.Hash
+}
+
+// brokenRestoreHash claims to restore its state but writes garbage into the
+// hash before failing.
+type brokenRestoreHash struct {
+	hash.Hash
+}
+
+func (h brokenRestoreHash) MarshalBinary() ([]byte, error) {
+	return []byte("state"), nil
+}
+
+func (h brokenRestoreHash) UnmarshalBinary([]byte) error {
+	h.Write([]byte("garbage"))
+	return errors.New("cannot restore")
+}
+
+// zeroSizeHash is a broken hash whose Sum appends nothing.
+type zeroSizeHash struct {
+	hash.Hash
+}
+
+func (zeroSizeHash) Size() int           { return 0 }
+func (zeroSizeHash) Sum(b []byte) []byte { return b }
+
+func TestMGF1ZeroSizeHash(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r != errEmptyMGF1Block {
+				t.Errorf("mgf1XOR: recovered %v, want errEmptyMGF1Block", r)
+			}
+		}()
+		mgf1XOR(make([]byte, 10), zeroSizeHash{sha256.New()}, []byte("seed"))
+	}()
+	if _, err := NewMGF1Reader(zeroSizeHash{sha256.New()}, []byte("seed")).Read(make([]byte, 10)); err != errEmptyMGF1Block {
+		t.Errorf("MGF1 reader: got %v, want errEmptyMGF1Block", err)
+	}
+	newHash := func() hash.Hash { return zeroSizeHash{sha256.New()} }
+	if _, err := SignPSSWith(nil, testKey(t), newHash, 0, nil, nil, WithoutBlinding()); err == nil {
+		t.Errorf("signing with a zero size hash succeeded")
+	}
+}
+
+func TestMGF1XORStateRestore(t *testing.T) {
+	for _, seedLen := range []int{0, 20, 64, 65, 1000} {
+		seed := bytes.Repeat([]byte{0xa5}, seedLen)
+		want := make([]byte, 300)
+		mgf1XOR(want, plainHash{sha256.New()}, seed)
+		got := make([]byte, 300)
+		mgf1XOR(got, sha256.New(), seed)
+		if !bytes.Equal(got, want) {
+			t.Errorf("seed length %d: restored state gives a different mask", seedLen)
+		}
+		got = make([]byte, 300)
+		mgf1XOR(got, brokenRestoreHash{sha256.New()}, seed)
+		if !bytes.Equal(got, want) {
+			t.Errorf("seed length %d: failed restore gives a different mask", seedLen)
+		}
+	}
+}
+
+func benchmarkMGF1XOR(b *testing.B, newHash func() hash.Hash, seedLen int) {
+	seed := make([]byte, seedLen)
+	out := make([]byte, 1024)
+	h := newHash()
+	b.SetBytes(int64(len(out)))
+	for i := 0; i < b.N; i++ {
+		mgf1XOR(out, h, seed)
+	}
+}
+
+func BenchmarkMGF1XOR(b *testing.B) {
+	restoring := func() hash.Hash { return sha256.New() }
+	rewriting := func() hash.Hash { return plainHash{sha256.New()} }
+	for _, seedLen := range []int{32, 1024} {
+		b.Run(fmt.Sprintf("restore/seed%d", seedLen), func(b *testing.B) {
+			benchmarkMGF1XOR(b, restoring, seedLen)
+		})
+		b.Run(fmt.Sprintf("rewrite/seed%d", seedLen), func(b *testing.B) {
+			benchmarkMGF1XOR(b, rewriting, seedLen)
+		})
+	}
+}
+
+// BenchmarkMGF1XORLargeMask masks the data block of a SHA-512 encoding for a
+// 16384 bit modulus.
+func BenchmarkMGF1XORLargeMask(b *testing.B) {
+	seed := make([]byte, sha512.Size)
+	out := make([]byte, 2048-sha512.Size-1)
+	h := sha512.New()
+	b.SetBytes(int64(len(out)))
+	for i := 0; i < b.N; i++ {
+		mgf1XOR(out, h, seed)
+	}
+}
+
+func TestMGF1Cache(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	hashed := sha256.Sum256([]byte("cached mask"))
+	sig, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], []byte("salt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewMGF1Cache(crypto.SHA256, 2)
+	for i := 0; i < 3; i++ {
+		if err := VerifyPSS(pub, crypto.SHA256, hashed[:], sig, 4, WithMGF1Cache(c)); err != nil {
+			t.Fatalf("verification %d: %v", i, err)
+		}
+	}
+	if c.hits != 2 {
+		t.Errorf("%d cache hits, want 2", c.hits)
+	}
+	want, err := ComputeExpectedEM(pub, crypto.SHA256, hashed[:], []byte("salt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		em, err := ComputeExpectedEM(pub, crypto.SHA256, hashed[:], []byte("salt"), WithMGF1Cache(c))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(em, want) {
+			t.Errorf("encoding %d with the cache differs", i)
+		}
+	}
+
+	// A cache for another hash function is left alone.
+	other := NewMGF1Cache(crypto.SHA1, 2)
+	for i := 0; i < 2; i++ {
+		if err := VerifyPSS(pub, crypto.SHA256, hashed[:], sig, 4, WithMGF1Cache(other)); err != nil {
+			t.Fatalf("verification with a SHA-1 cache: %v", err)
+		}
+	}
+	if other.hits != 0 || len(other.masks) != 0 {
+		t.Errorf("SHA-1 cache used for SHA-256: %d hits, %d masks", other.hits, len(other.masks))
+	}
+
+	// The cache holds at most its number of entries.
+	h := sha256.New()
+	for i := 0; i < 5; i++ {
+		c.xor(make([]byte, 10), h, []byte{byte(i)})
+	}
+	if len(c.masks) != 2 || len(c.order) != 2 {
+		t.Errorf("cache holds %d masks, want 2", len(c.masks))
+	}
+}
@@ -0,0 +1,88 @@
+package pss
+
+import (
+	"crypto"
+	"hash"
+	"reflect"
+	"sync"
+)
+
+// An MGF1Cache remembers the most recently generated MGF1 masks for one hash
+// function, so that a batch of operations that reuse the same seed, as test
+// suites and some constructions do, generates each mask only once. In
+// ordinary signing every seed is new and the cache only costs memory.
+//
+// A cache must not be shared between security contexts: whether a mask was
+// cached shows in the time an operation takes, which tells one user of the
+// cache that another has used the same seed. An MGF1Cache is safe for
+// concurrent use.
+type MGF1Cache struct {
+	typ  reflect.Type
+	size int
+
+	mu      sync.Mutex
+	masks   map[mgf1CacheKey][]byte
+	order   []mgf1CacheKey // oldest first
+	entries int
+	hits    int
+}
+
+type mgf1CacheKey struct {
+	seed   string
+	length int
+}
+
+// NewMGF1Cache returns a cache of at most entries masks generated with
+// hash.
+func NewMGF1Cache(hash crypto.Hash, entries int) *MGF1Cache {
+	h := hash.New()
+	return &MGF1Cache{
+		typ:     reflect.TypeOf(h),
+		size:    h.Size(),
+		masks:   make(map[mgf1CacheKey][]byte),
+		entries: entries,
+	}
+}
+
+// WithMGF1Cache makes MGF1 look masks up in c and add the ones it
+// generates. The cache is only used when the hash function of MGF1 is the
+// one c was made for. By default nothing is cached.
+func WithMGF1Cache(c *MGF1Cache) Option {
+	return func(o *options) {
+		o.mgfCache = c
+	}
+}
+
+// xor masks out with MGF1 of seed using h, from the cache if it can. It
+// reports false, leaving out alone, if h is not the hash of the cache.
+func (c *MGF1Cache) xor(out []byte, h hash.Hash, seed []byte) bool {
+	if reflect.TypeOf(h) != c.typ || h.Size() != c.size || c.entries <= 0 {
+		return false
+	}
+	key := mgf1CacheKey{seed: string(seed), length: len(out)}
+	c.mu.Lock()
+	mask, ok := c.masks[key]
+	if ok {
+		c.hits++
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		mask = make([]byte, len(out))
+		mgf1XOR(mask, h, seed)
+		c.mu.Lock()
+		if _, ok := c.masks[key]; !ok {
+			if len(c.order) == c.entries {
+				delete(c.masks, c.order[0])
+				c.order = append(c.order[:0], c.order[1:]...)
+			}
+			c.masks[key] = mask
+			c.order = append(c.order, key)
+		}
+		c.mu.Unlock()
+	}
+	for i := range out {
+		out[i] ^= mask[i]
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ParsePublicKeyPEM returns the RSA public key in the first PEM block of
+// data, which is either a "PUBLIC KEY" block holding a SubjectPublicKeyInfo,
+// as written by
+//
+//	openssl pkey -in key.pem -pubout -out pub.pem
+//
+// or a PKCS #1 "RSA PUBLIC KEY" block.
+func ParsePublicKeyPEM(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("crypto/rsa: no PEM block found")
+	}
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("crypto/rsa: PEM block holds a %T, not an RSA public key", key)
+		}
+		return pub, nil
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	return nil, fmt.Errorf("crypto/rsa: unexpected PEM block type %q", block.Type)
+}
+
+// OpenSSLSaltLength returns the salt length to pass to VerifyPSS for a
+// signature made by OpenSSL with the option -sigopt rsa_pss_saltlen:saltlen
+// and the given key and hash. A signature file written by
+//
+//	openssl dgst -sha256 -sigopt rsa_padding_mode:pss \
+//		-sigopt rsa_pss_saltlen:digest -sign key.pem -out msg.sig msg.txt
+//
+// holds the raw signature and is verified by hashing msg.txt with SHA-256
+// and calling
+//
+//	sLen, err := OpenSSLSaltLength(pub, crypto.SHA256, "digest")
+//	if err != nil {
+//		return err
+//	}
+//	err = VerifyPSS(pub, crypto.SHA256, hashed, sig, sLen)
+//
+// saltlen is one of "digest", for a salt as long as the hash, "max", for the
+// longest salt that fits the key, "auto", which lets the verifier recover
+// the length, or a decimal number of bytes. An empty saltlen stands for
+// OpenSSL's default when signing, which is "max" and not the hash length:
+// such signatures fail VerifyPSS with sLen = hash.Size(). OpenSSL's numeric
+// forms of the names are accepted too: -1 for "digest", -2 for "auto", which
+// gives the longest salt when OpenSSL signs and is verified like "auto", and
+// -3 for "max". If OpenSSL is also given -sigopt rsa_mgf1_md:name, pass
+// WithMGFHash with that hash too.
+//
+// Any other saltlen is an error rather than SaltLengthAuto, so that a typing
+// mistake does not turn a check of the salt length into its recovery. So
+// is a hash that is not available or a salt length the key cannot hold.
+func OpenSSLSaltLength(pub *rsa.PublicKey, hash crypto.Hash, saltlen string) (int, error) {
+	if !hash.Available() {
+		return 0, fmt.Errorf("crypto/rsa: hash function %v not available", hash)
+	}
+	var sLen int
+	switch saltlen {
+	case "digest", "-1":
+		sLen = hash.Size()
+	case "max", "-3", "":
+		sLen = MaxSaltLength(pub, hash)
+		if sLen < 0 {
+			return 0, fmt.Errorf("crypto/rsa: %d bit key too small for PSS with %v", pub.N.BitLen(), hash)
+		}
+	case "auto", "-2":
+		return SaltLengthAuto, nil
+	default:
+		n, err := strconv.Atoi(saltlen)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("crypto/rsa: unknown OpenSSL salt length %q", saltlen)
+		}
+		sLen = n
+	}
+	if err := IsValidPSSConfig(pub.N.BitLen(), hash, sLen); err != nil {
+		return 0, err
+	}
+	return sLen, nil
+}
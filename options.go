@@ -0,0 +1,372 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"hash"
+	"log"
+	"math/big"
+)
+
+// An Option changes the behaviour of a signing or verification function.
+// Options that do not apply to an operation are ignored by it.
+type Option func(*options)
+
+type options struct {
+	blindingFailure BlindingFailurePolicy
+	logger          *log.Logger
+	saltCheck       func(salt []byte) bool
+	costHook        func(SignCost)
+	tracer          Tracer
+	constantTime    bool
+
+	missingLeadingZero    bool
+	trailer               []byte
+	saltNotLongerThanHash bool
+	exponentiator         Exponentiator
+	newDigester           func() Digester
+	mgfHash               crypto.Hash
+	mgfSeedLength         int
+	maxModulusBits        int
+	minModulusBits        int
+	warnRawMessage        bool
+	noBlinding            bool
+	mgfCache              *MGF1Cache
+	saltSource            DigestSaltSource
+	progressInterval      int64
+	progress              func(processed int64)
+	hashCompare           func(a, b []byte) int
+	selfVerify            bool
+	faults                *FaultCounter
+	identityHashLen       int
+	identityHashErr       error
+
+	// mgfWrap, if set, wraps every hash instance used for MGF1.
+	mgfWrap func(hash.Hash) hash.Hash
+
+	// saltLengthFound, if set, is called with the salt length of a
+	// signature that verified.
+	saltLengthFound func(sLen int)
+}
+
+func newOptions(opts []Option) *options {
+	o := new(options)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func (o *options) logf(format string, v ...interface{}) {
+	if o.logger != nil {
+		o.logger.Printf(format, v...)
+		return
+	}
+	log.Printf(format, v...)
+}
+
+// WithWarnOnLikelyRawMessage makes signing and verification log a warning
+// to the logger of WithLogger if the digest they are given consists only of
+// printable ASCII, which suggests that the message itself was passed
+// instead of its hash. The output of a hash function is all printable
+// ASCII with a probability below 10^-13 for a 32 byte digest. It is a
+// development aid and off by default.
+func WithWarnOnLikelyRawMessage() Option {
+	return func(o *options) {
+		o.warnRawMessage = true
+	}
+}
+
+// warnIfRawMessage logs a warning if WithWarnOnLikelyRawMessage is set and
+// hashed looks like text.
+func (o *options) warnIfRawMessage(hashed []byte) {
+	if !o.warnRawMessage || len(hashed) == 0 || o.identityHashLen > 0 {
+		return
+	}
+	for _, b := range hashed {
+		if (b < 0x20 || b > 0x7e) && b != '\t' && b != '\n' && b != '\r' {
+			return
+		}
+	}
+	o.logf("crypto/rsa: warning: the %d byte digest is printable text; was the message passed instead of its hash?", len(hashed))
+}
+
+// BlindingFailurePolicy decides what signing does when RSA blinding cannot
+// be performed because the random source failed.
+type BlindingFailurePolicy int
+
+const (
+	// FailClosed aborts signing and returns the error of the random
+	// source. This is the default.
+	FailClosed BlindingFailurePolicy = iota
+
+	// FallBackUnblinded logs a warning and signs without blinding. The
+	// signature is the same, but the private key operation is then exposed
+	// to timing side channels.
+	FallBackUnblinded
+)
+
+// WithBlindingFailurePolicy sets the policy applied by the signing
+// functions when the random source used for blinding fails.
+func WithBlindingFailurePolicy(p BlindingFailurePolicy) Option {
+	return func(o *options) {
+		o.blindingFailure = p
+	}
+}
+
+// WithoutBlinding makes signing carry out the private key operation without
+// RSA blinding, so that it reads no randomness. This is INSECURE for
+// production use: an unblinded private key operation may leak the key
+// through timing. It exists for benchmarking and profiling the
+// exponentiation itself. RawDecrypt honours it too. Without this option the
+// signing functions and RawDecrypt reject a nil random source.
+func WithoutBlinding() Option {
+	return func(o *options) {
+		o.noBlinding = true
+	}
+}
+
+// WithLogger sets the logger used for warnings. By default warnings go to
+// the standard logger of package log.
+func WithLogger(l *log.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithConstantTimeVerify makes verification carry out every step of the
+// EMSA-PSS consistency check, whatever the outcome of the earlier ones, and
+// decide only at the end. A forged signature then takes the same sequence
+// of operations whether it fails on the trailer, the padding or the hash.
+// Checks that involve only the lengths of the key, hash and salt still
+// return early, and so does recovering the salt length for SaltLengthAuto.
+// The public key operation itself uses math/big, whose running time
+// depends on the number of machine words of its result and so on the
+// leading zero bytes of the encoded message; this option does not hide
+// that.
+func WithConstantTimeVerify() Option {
+	return func(o *options) {
+		o.constantTime = true
+	}
+}
+
+// WithTolerateMissingLeadingZero makes verification accept a signature that
+// is one byte shorter than the modulus, treating it as if it had a leading
+// zero byte. Some non-conforming signers strip that byte when the signature
+// happens to start with one. This is an interoperability workaround and is
+// off by default; conforming signatures are always exactly as long as the
+// modulus.
+func WithTolerateMissingLeadingZero() Option {
+	return func(o *options) {
+		o.missingLeadingZero = true
+	}
+}
+
+// DefaultMaxModulusBits is the largest modulus, in bits, that verification
+// and ValidatePublicKey accept unless WithMaxModulusBits says otherwise.
+const DefaultMaxModulusBits = 16384
+
+// ErrModulusTooLarge is returned for a key whose modulus is larger than
+// the limit set with WithMaxModulusBits.
+var ErrModulusTooLarge = errors.New("crypto/rsa: modulus too large")
+
+// WithMaxModulusBits sets the largest modulus, in bits, that verification
+// and ValidatePublicKey accept. A larger key is rejected with
+// ErrModulusTooLarge before any modular arithmetic, which bounds the work a
+// peer can cause by presenting an oversized key. Zero or less selects
+// DefaultMaxModulusBits.
+func WithMaxModulusBits(bits int) Option {
+	return func(o *options) {
+		o.maxModulusBits = bits
+	}
+}
+
+// ErrModulusTooSmall is returned for a key whose modulus is smaller than
+// the minimum set with WithMinModulusBits.
+var ErrModulusTooSmall = errors.New("crypto/rsa: modulus smaller than the policy minimum")
+
+// WithMinModulusBits sets the smallest modulus, in bits, that verification
+// and ValidatePublicKey accept. A smaller key is rejected with
+// ErrModulusTooSmall, which keeps an attacker from substituting a weak key
+// that can be factored. There is no minimum by default. NIST SP 800-131A
+// allows no less than 2048 bits, and SP 800-57 recommends 3072 bits for
+// signatures that must stay secure beyond 2030.
+func WithMinModulusBits(bits int) Option {
+	return func(o *options) {
+		o.minModulusBits = bits
+	}
+}
+
+// checkModulusSize returns an error if n is larger than the limit of
+// WithMaxModulusBits or smaller than that of WithMinModulusBits.
+func (o *options) checkModulusSize(n *big.Int) error {
+	max := o.maxModulusBits
+	if max <= 0 {
+		max = DefaultMaxModulusBits
+	}
+	bits := n.BitLen()
+	if bits > max {
+		return fmt.Errorf("%w: %d bits, limit is %d", ErrModulusTooLarge, bits, max)
+	}
+	if bits < o.minModulusBits {
+		return fmt.Errorf("%w: %d bits, minimum is %d", ErrModulusTooSmall, bits, o.minModulusBits)
+	}
+	return nil
+}
+
+// WithRejectSaltLongerThanHash makes verification fail with ErrSaltTooLong
+// if the salt is longer than the output of the hash function, as some
+// profiles following NIST guidance require. It applies both to a salt
+// length given by the caller and to one recovered with SaltLengthAuto.
+func WithRejectSaltLongerThanHash() Option {
+	return func(o *options) {
+		o.saltNotLongerThanHash = true
+	}
+}
+
+// defaultTrailer is the trailer of RFC 3447, trailerField 1 in the ASN.1
+// RSASSA-PSS-params.
+var defaultTrailer = []byte{0xBC}
+
+// WithTrailer sets the trailer that ends the encoded message when signing
+// and that is expected when verifying. The default is the single octet 0xbc
+// of RFC 3447, which is what nearly every signer uses; other trailers are
+// only needed for strict conformance to RSASSA-PSS-params with a different
+// trailerField, such as the two octet trailers of IEEE 1363 and ISO/IEC
+// 9796-2 that consist of a hash identifier followed by 0xcc. A nil or empty
+// trailer selects the default. The trailer is copied, so the caller may
+// reuse its slice.
+func WithTrailer(trailer []byte) Option {
+	trailer = append([]byte(nil), trailer...)
+	return func(o *options) {
+		o.trailer = trailer
+	}
+}
+
+func (o *options) trailerField() []byte {
+	if len(o.trailer) == 0 {
+		return defaultTrailer
+	}
+	return o.trailer
+}
+
+// WithMGFHash makes MGF1 use hash instead of the hash function of the
+// message digest. RFC 3447 allows the two to differ, and some profiles and
+// hardware tokens use, for example, SHA-256 for the digest and SHA-1 for
+// MGF1. Signer and verifier have to agree; the default is the digest hash.
+// Signing and verification fail with an error if hash is not available.
+func WithMGFHash(hash crypto.Hash) Option {
+	return func(o *options) {
+		o.mgfHash = hash
+	}
+}
+
+// checkMGFHash returns an error if the hash of WithMGFHash is not linked
+// into the binary, rather than letting mgfNewHash panic.
+func (o *options) checkMGFHash() error {
+	if o.mgfHash != 0 && !o.mgfHash.Available() {
+		return fmt.Errorf("crypto/rsa: MGF1 hash function %v not available", o.mgfHash)
+	}
+	return nil
+}
+
+// mgfNewHash returns the constructor of the hash for MGF1: that of the
+// WithMGFHash option, or newHash.
+func (o *options) mgfNewHash(newHash func() hash.Hash) func() hash.Hash {
+	if o.mgfHash != 0 {
+		newHash = o.mgfHash.New
+	}
+	if o.mgfWrap != nil {
+		return func() hash.Hash { return o.mgfWrap(newHash()) }
+	}
+	return newHash
+}
+
+// mgf1XOR masks out with MGF1 of seed, using the hash of mgfNewHash.
+func (o *options) mgf1XOR(out []byte, newHash func() hash.Hash, seed []byte) {
+	if o.mgfCache != nil {
+		h := o.mgfNewHash(newHash)()
+		if o.mgfCache.xor(out, h, seed) {
+			return
+		}
+		mgf1XOR(out, h, seed)
+		return
+	}
+	mgf1XOR(out, o.mgfNewHash(newHash)(), seed)
+}
+
+// WithMGFSeedLength makes MGF1 take only the leftmost n octets of H as its
+// seed, for interoperating with implementations that do so. RFC 3447 seeds
+// MGF1 with all of H, so any n other than the length of the hash breaks
+// conformance and the signatures verify only with the same setting. Zero,
+// the default, selects the whole of H.
+func WithMGFSeedLength(n int) Option {
+	return func(o *options) {
+		o.mgfSeedLength = n
+	}
+}
+
+// mgfSeed returns the part of h that seeds MGF1.
+func (o *options) mgfSeed(h []byte) ([]byte, error) {
+	n := o.mgfSeedLength
+	if n == 0 {
+		return h, nil
+	}
+	if n < 0 || n > len(h) {
+		return nil, fmt.Errorf("crypto/rsa: MGF1 seed length %d out of range for a %d byte hash", n, len(h))
+	}
+	return h[:n], nil
+}
+
+// WithHashCompare makes verification compare H with H' using compare,
+// which returns 1 if a and b are equal and 0 otherwise, like
+// subtle.ConstantTimeCompare, the default. It is an advanced option for a
+// hardware-assisted comparison or for counting comparisons to detect
+// forgery attempts. compare must run in constant time, or verification
+// leaks how much of a forged hash matched. It is called once for each
+// encoded message whose padding passes the earlier checks, and once for
+// every encoded message with WithConstantTimeVerify.
+func WithHashCompare(compare func(a, b []byte) int) Option {
+	return func(o *options) {
+		o.hashCompare = compare
+	}
+}
+
+// compareHash compares H' with H.
+func (o *options) compareHash(a, b []byte) int {
+	if o.hashCompare != nil {
+		return o.hashCompare(a, b)
+	}
+	return subtle.ConstantTimeCompare(a, b)
+}
+
+// WithUnsafeIdentityHash makes verification take hashed as the message
+// itself, of exactly mLen bytes, and use it verbatim as mHash, as if the
+// message had been hashed with the identity function. It is for legacy
+// systems that sign short, fixed-length messages without hashing them. The
+// hash function given to the verification function is still used for
+// H = Hash(M') and for MGF1. Such signatures do not conform to RFC 3447,
+// and a message is only as hard to forge a signature for as it is long, so
+// this must never be used for new signatures. Signing ignores the option
+// and keeps rejecting input that is not a digest. mLen must be positive;
+// otherwise every verification fails.
+func WithUnsafeIdentityHash(mLen int) Option {
+	return func(o *options) {
+		o.identityHashLen = mLen
+		o.identityHashErr = nil
+		if mLen <= 0 {
+			o.identityHashErr = fmt.Errorf("crypto/rsa: identity hash message length %d is not positive", mLen)
+		}
+	}
+}
+
+// mHashLen returns the length that mHash must have for a hash function
+// with output of hLen bytes.
+func (o *options) mHashLen(hLen int) int {
+	if o.identityHashLen > 0 {
+		return o.identityHashLen
+	}
+	return hLen
+}
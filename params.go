@@ -0,0 +1,93 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Version is the version of this package, for recording which
+// implementation produced a signature.
+const Version = "0.1.0"
+
+// PSSOptions holds the parameters of an RSASSA-PSS signature, which signer
+// and verifier have to agree on.
+type PSSOptions struct {
+	// Hash is the hash function used for the message digest and, unless
+	// MGFHash is set, for MGF1.
+	Hash crypto.Hash
+
+	// MGFHash, if not zero, is the hash function used for MGF1; see
+	// WithMGFHash.
+	MGFHash crypto.Hash
+
+	// SaltLength is the length of the salt in bytes.
+	SaltLength int
+
+	// Trailer is the trailer that ends the encoded message. Nil selects
+	// the 0xbc of RFC 3447; see WithTrailer.
+	Trailer []byte
+}
+
+// options returns the options for pssOpts followed by opts, so that an
+// explicit option takes precedence over the parameters.
+func (pssOpts *PSSOptions) options(opts []Option) []Option {
+	return append([]Option{WithTrailer(pssOpts.Trailer), WithMGFHash(pssOpts.MGFHash)}, opts...)
+}
+
+// PS256 returns the parameters of the JWA algorithm PS256: SHA-256, MGF1
+// with SHA-256 and a 32 byte salt.
+func PS256() *PSSOptions {
+	return &PSSOptions{Hash: crypto.SHA256, SaltLength: crypto.SHA256.Size()}
+}
+
+// PS384 returns the parameters of the JWA algorithm PS384: SHA-384, MGF1
+// with SHA-384 and a 48 byte salt.
+func PS384() *PSSOptions {
+	return &PSSOptions{Hash: crypto.SHA384, SaltLength: crypto.SHA384.Size()}
+}
+
+// PS512 returns the parameters of the JWA algorithm PS512: SHA-512, MGF1
+// with SHA-512 and a 64 byte salt.
+func PS512() *PSSOptions {
+	return &PSSOptions{Hash: crypto.SHA512, SaltLength: crypto.SHA512.Size()}
+}
+
+// AlgorithmName returns a name for RSASSA-PSS with hash and a salt of sLen
+// bytes, such as "RSASSA-PSS-SHA256-salt32", for logs and signature
+// metadata. A salt length of SaltLengthAuto gives the suffix "salt-auto".
+func AlgorithmName(hash crypto.Hash, sLen int) string {
+	salt := "salt" + strconv.Itoa(sLen)
+	if sLen == SaltLengthAuto {
+		salt = "salt-auto"
+	}
+	return "RSASSA-PSS-" + strings.ReplaceAll(hash.String(), "-", "") + "-" + salt
+}
+
+var errNilPSSOptions = errors.New("crypto/rsa: nil PSSOptions")
+
+// SignPSSWithOpts signs hashed with the parameters in pssOpts, reading a
+// salt of pssOpts.SaltLength bytes from rand.
+func SignPSSWithOpts(rand io.Reader, priv *rsa.PrivateKey, hashed []byte, pssOpts *PSSOptions, opts ...Option) ([]byte, error) {
+	if pssOpts == nil {
+		return nil, errNilPSSOptions
+	}
+	o := newOptions(pssOpts.options(opts))
+	salt, err := generateSalt(rand, hashed, pssOpts.SaltLength, o)
+	if err != nil {
+		return nil, err
+	}
+	return signPSS(rand, priv, pssOpts.Hash, hashed, salt, o)
+}
+
+// VerifyPSSWithOpts verifies sig as a signature of hashed with the
+// parameters in pssOpts.
+func VerifyPSSWithOpts(pub *rsa.PublicKey, hashed []byte, sig []byte, pssOpts *PSSOptions, opts ...Option) error {
+	if pssOpts == nil {
+		return errNilPSSOptions
+	}
+	return VerifyPSS(pub, pssOpts.Hash, hashed, sig, pssOpts.SaltLength, pssOpts.options(opts)...)
+}
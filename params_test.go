@@ -0,0 +1,114 @@
+package pss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/big"
+	"testing"
+)
+
+func TestSignPSSWithOpts(t *testing.T) {
+	priv := testKey(t)
+	for _, opts := range []*PSSOptions{PS256(), PS384(), PS512()} {
+		if opts.SaltLength != opts.Hash.Size() {
+			t.Errorf("%v: salt length %d, want the hash size", opts.Hash, opts.SaltLength)
+		}
+		h := opts.Hash.New()
+		h.Write([]byte("typed options"))
+		hashed := h.Sum(nil)
+		sig, err := SignPSSWithOpts(rand.Reader, priv, hashed, opts)
+		if err != nil {
+			t.Fatalf("%v: %v", opts.Hash, err)
+		}
+		if err := VerifyPSSWithOpts(&priv.PublicKey, hashed, sig, opts); err != nil {
+			t.Errorf("%v: %v", opts.Hash, err)
+		}
+		stdOpts := &rsa.PSSOptions{SaltLength: opts.SaltLength}
+		if err := rsa.VerifyPSS(&priv.PublicKey, opts.Hash, hashed, sig, stdOpts); err != nil {
+			t.Errorf("%v: crypto/rsa rejects the signature: %v", opts.Hash, err)
+		}
+	}
+	if _, err := SignPSSWithOpts(rand.Reader, priv, make([]byte, 32), nil); err == nil {
+		t.Errorf("signing with nil options succeeded")
+	}
+	if err := VerifyPSSWithOpts(&priv.PublicKey, make([]byte, 32), nil, &PSSOptions{Hash: crypto.SHA256}); err == nil {
+		t.Errorf("verifying an empty signature succeeded")
+	}
+}
+
+func TestSignPSSMGFHash(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	hashed := sha256.Sum256([]byte("MGF hash"))
+	salt := []byte("salt with SHA-1 MGF1")
+	mgf := WithMGFHash(crypto.SHA1)
+	sig, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], salt, mgf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Unmask DB with SHA-1 by hand and check that it holds the salt.
+	em := RawEncrypt(pub, new(big.Int).SetBytes(sig)).FillBytes(make([]byte, (pub.N.BitLen()+6)/8))
+	db := em[:len(em)-sha256.Size-1]
+	h := em[len(db) : len(em)-1]
+	mask := make([]byte, len(db))
+	io.ReadFull(NewMGF1Reader(sha1.New(), h), mask)
+	for i := range db {
+		db[i] ^= mask[i]
+	}
+	db[0] &= 0x7F
+	if !bytes.HasSuffix(db, append([]byte{0x01}, salt...)) {
+		t.Errorf("data block unmasked with SHA-1 does not end in 0x01 || salt")
+	}
+
+	for _, sLen := range []int{len(salt), SaltLengthAuto} {
+		if err := VerifyPSS(pub, crypto.SHA256, hashed[:], sig, sLen, mgf); err != nil {
+			t.Errorf("sLen %d: %v", sLen, err)
+		}
+		if err := VerifyPSS(pub, crypto.SHA256, hashed[:], sig, sLen); err != rsa.ErrVerification {
+			t.Errorf("sLen %d without the MGF hash: got %v, want rsa.ErrVerification", sLen, err)
+		}
+	}
+	got, err := TryVerifyPSSSaltLengths(pub, crypto.SHA256, hashed[:], sig, nil, mgf)
+	if err != nil || got != len(salt) {
+		t.Errorf("recovered salt length %d, %v; want %d", got, err, len(salt))
+	}
+
+	pssOpts := &PSSOptions{Hash: crypto.SHA256, MGFHash: crypto.SHA1, SaltLength: len(salt)}
+	if err := VerifyPSSWithOpts(pub, hashed[:], sig, pssOpts); err != nil {
+		t.Errorf("VerifyPSSWithOpts: %v", err)
+	}
+	// MD4 is not linked into the test binary.
+	for _, h := range []crypto.Hash{crypto.MD4, crypto.Hash(200)} {
+		unavailable := WithMGFHash(h)
+		if _, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], salt, unavailable); err == nil {
+			t.Errorf("%v: signing succeeded", h)
+		}
+		if err := VerifyPSS(pub, crypto.SHA256, hashed[:], sig, len(salt), unavailable); err == nil || errors.Is(err, rsa.ErrVerification) {
+			t.Errorf("%v: verification returned %v, want an unavailable hash error", h, err)
+		}
+	}
+}
+
+func TestAlgorithmName(t *testing.T) {
+	for _, test := range []struct {
+		hash crypto.Hash
+		sLen int
+		want string
+	}{
+		{crypto.SHA256, 32, "RSASSA-PSS-SHA256-salt32"},
+		{crypto.SHA1, 0, "RSASSA-PSS-SHA1-salt0"},
+		{crypto.SHA512_256, 32, "RSASSA-PSS-SHA512/256-salt32"},
+		{crypto.SHA384, SaltLengthAuto, "RSASSA-PSS-SHA384-salt-auto"},
+	} {
+		if got := AlgorithmName(test.hash, test.sLen); got != test.want {
+			t.Errorf("AlgorithmName(%v, %d) = %q, want %q", test.hash, test.sLen, got, test.want)
+		}
+	}
+}
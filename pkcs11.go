@@ -0,0 +1,124 @@
+package pss
+
+import (
+	"crypto"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// PKCS11MechanismRSAPKCSPSS is the PKCS #11 mechanism CKM_RSA_PKCS_PSS,
+// which signs a digest computed by the caller, like SignPSS. Its parameter
+// is a CK_RSA_PKCS_PSS_PARAMS structure, see PKCS11PSSParams.
+const PKCS11MechanismRSAPKCSPSS = 0x0000000D
+
+// pkcs11Hashes maps hash functions to their CKM_ digest mechanism and CKG_
+// MGF1 generator, as defined by PKCS #11 v2.40 and v3.0.
+var pkcs11Hashes = map[crypto.Hash]struct{ mechanism, mgf uint64 }{
+	crypto.SHA1:     {0x00000220, 0x00000001},
+	crypto.SHA224:   {0x00000255, 0x00000005},
+	crypto.SHA256:   {0x00000250, 0x00000002},
+	crypto.SHA384:   {0x00000260, 0x00000003},
+	crypto.SHA512:   {0x00000270, 0x00000004},
+	crypto.SHA3_224: {0x000002B5, 0x00000006},
+	crypto.SHA3_256: {0x000002B0, 0x00000007},
+	crypto.SHA3_384: {0x000002C0, 0x00000008},
+	crypto.SHA3_512: {0x000002D0, 0x00000009},
+}
+
+// PKCS11PSSParams holds the fields of a PKCS #11 CK_RSA_PKCS_PSS_PARAMS
+// structure: the CKM_ mechanism of the message hash, the CKG_ mask
+// generation function and the salt length in bytes.
+type PKCS11PSSParams struct {
+	HashAlg    uint64
+	MGF        uint64
+	SaltLength uint64
+}
+
+// NewPKCS11PSSParams returns the PKCS #11 parameters for signatures with
+// hash, MGF1 with mgfHash and a salt of sLen bytes. mgfHash is the same as
+// hash unless the signatures are made with WithMGFHash.
+func NewPKCS11PSSParams(hash, mgfHash crypto.Hash, sLen int) (PKCS11PSSParams, error) {
+	h, ok := pkcs11Hashes[hash]
+	if !ok {
+		return PKCS11PSSParams{}, fmt.Errorf("crypto/rsa: no PKCS #11 mechanism for hash %v", hash)
+	}
+	mgf, ok := pkcs11Hashes[mgfHash]
+	if !ok {
+		return PKCS11PSSParams{}, fmt.Errorf("crypto/rsa: no PKCS #11 MGF1 generator for hash %v", mgfHash)
+	}
+	if sLen < 0 {
+		return PKCS11PSSParams{}, errNegativeSaltLength
+	}
+	return PKCS11PSSParams{HashAlg: h.mechanism, MGF: mgf.mgf, SaltLength: uint64(sLen)}, nil
+}
+
+// Hashes returns the message hash, the MGF1 hash and the salt length that p
+// describes.
+func (p PKCS11PSSParams) Hashes() (hash, mgfHash crypto.Hash, sLen int, err error) {
+	for h, v := range pkcs11Hashes {
+		if v.mechanism == p.HashAlg {
+			hash = h
+		}
+		if v.mgf == p.MGF {
+			mgfHash = h
+		}
+	}
+	if hash == 0 {
+		return 0, 0, 0, fmt.Errorf("crypto/rsa: unknown PKCS #11 hash mechanism %#x", p.HashAlg)
+	}
+	if mgfHash == 0 {
+		return 0, 0, 0, fmt.Errorf("crypto/rsa: unknown PKCS #11 MGF %#x", p.MGF)
+	}
+	if p.SaltLength > uint64(maxInt) {
+		return 0, 0, 0, errors.New("crypto/rsa: PKCS #11 salt length out of range")
+	}
+	return hash, mgfHash, int(p.SaltLength), nil
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+var errPKCS11ULongSize = errors.New("crypto/rsa: CK_ULONG size must be 4 or 8 bytes")
+
+// Marshal returns p laid out in memory as the C structure, three CK_ULONG
+// fields of ulongSize bytes each in byte order order. CK_ULONG is an unsigned
+// long, so ulongSize is 8 on most 64 bit Unix systems and 4 on Windows and
+// 32 bit systems; the token's library and platform decide.
+func (p PKCS11PSSParams) Marshal(ulongSize int, order binary.ByteOrder) ([]byte, error) {
+	fields := []uint64{p.HashAlg, p.MGF, p.SaltLength}
+	b := make([]byte, 3*ulongSize)
+	for i, v := range fields {
+		switch ulongSize {
+		case 4:
+			if v > 0xFFFFFFFF {
+				return nil, fmt.Errorf("crypto/rsa: PKCS #11 parameter %#x does not fit in 4 bytes", v)
+			}
+			order.PutUint32(b[4*i:], uint32(v))
+		case 8:
+			order.PutUint64(b[8*i:], v)
+		default:
+			return nil, errPKCS11ULongSize
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalPKCS11PSSParams parses a CK_RSA_PKCS_PSS_PARAMS structure laid
+// out as described for Marshal.
+func UnmarshalPKCS11PSSParams(b []byte, ulongSize int, order binary.ByteOrder) (PKCS11PSSParams, error) {
+	if ulongSize != 4 && ulongSize != 8 {
+		return PKCS11PSSParams{}, errPKCS11ULongSize
+	}
+	if len(b) != 3*ulongSize {
+		return PKCS11PSSParams{}, fmt.Errorf("crypto/rsa: CK_RSA_PKCS_PSS_PARAMS is %d bytes, want %d", len(b), 3*ulongSize)
+	}
+	var fields [3]uint64
+	for i := range fields {
+		if ulongSize == 4 {
+			fields[i] = uint64(order.Uint32(b[4*i:]))
+		} else {
+			fields[i] = order.Uint64(b[8*i:])
+		}
+	}
+	return PKCS11PSSParams{HashAlg: fields[0], MGF: fields[1], SaltLength: fields[2]}, nil
+}
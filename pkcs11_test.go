@@ -0,0 +1,79 @@
+package pss
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/binary"
+	"testing"
+)
+
+func TestPKCS11PSSParams(t *testing.T) {
+	p, err := NewPKCS11PSSParams(crypto.SHA256, crypto.SHA256, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (PKCS11PSSParams{HashAlg: 0x250, MGF: 0x2, SaltLength: 32}); p != want {
+		t.Errorf("got %+v, want %+v", p, want)
+	}
+	for h := range pkcs11Hashes {
+		p, err := NewPKCS11PSSParams(h, crypto.SHA1, 20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash, mgfHash, sLen, err := p.Hashes()
+		if err != nil || hash != h || mgfHash != crypto.SHA1 || sLen != 20 {
+			t.Errorf("%v: round trip gave %v, %v, %d, %v", h, hash, mgfHash, sLen, err)
+		}
+	}
+	if _, err := NewPKCS11PSSParams(crypto.MD5, crypto.SHA256, 16); err == nil {
+		t.Errorf("MD5 accepted")
+	}
+	if _, err := NewPKCS11PSSParams(crypto.SHA256, crypto.SHA256, -1); err == nil {
+		t.Errorf("negative salt length accepted")
+	}
+	if _, _, _, err := (PKCS11PSSParams{HashAlg: 0x250, MGF: 0x42}).Hashes(); err == nil {
+		t.Errorf("unknown MGF accepted")
+	}
+}
+
+func TestPKCS11PSSParamsMarshal(t *testing.T) {
+	p := PKCS11PSSParams{HashAlg: 0x250, MGF: 0x2, SaltLength: 32}
+	tests := []struct {
+		ulongSize int
+		order     binary.ByteOrder
+		want      []byte
+	}{
+		{4, binary.LittleEndian, []byte{
+			0x50, 0x02, 0, 0,
+			0x02, 0, 0, 0,
+			0x20, 0, 0, 0,
+		}},
+		{8, binary.BigEndian, []byte{
+			0, 0, 0, 0, 0, 0, 0x02, 0x50,
+			0, 0, 0, 0, 0, 0, 0, 0x02,
+			0, 0, 0, 0, 0, 0, 0, 0x20,
+		}},
+	}
+	for _, test := range tests {
+		b, err := p.Marshal(test.ulongSize, test.order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, test.want) {
+			t.Errorf("ulongSize=%d: got %x, want %x", test.ulongSize, b, test.want)
+		}
+		got, err := UnmarshalPKCS11PSSParams(b, test.ulongSize, test.order)
+		if err != nil || got != p {
+			t.Errorf("ulongSize=%d: unmarshal gave %+v, %v", test.ulongSize, got, err)
+		}
+	}
+	if _, err := p.Marshal(2, binary.LittleEndian); err == nil {
+		t.Errorf("2 byte CK_ULONG accepted")
+	}
+	if _, err := (PKCS11PSSParams{SaltLength: 1 << 32}).Marshal(4, binary.LittleEndian); err == nil {
+		t.Errorf("salt length overflowing 4 bytes accepted")
+	}
+	if _, err := UnmarshalPKCS11PSSParams(make([]byte, 23), 8, binary.LittleEndian); err == nil {
+		t.Errorf("short structure accepted")
+	}
+}
@@ -6,17 +6,42 @@ package pss
 import (
 	"crypto"
 	"crypto/rsa"
+	"crypto/subtle"
 
 	"errors"
+	"fmt"
 	"hash"
 	"io"
 	"math/big"
+	"time"
 )
 
-func emsaPSSEncode(mHash []byte, emBits int, salt []byte, hash hash.Hash) ([]byte, error) {
+// mPrimePrefix holds the eight zero octets that M' starts with. Writing it
+// from a package variable saves an allocation on every signature and
+// verification.
+var mPrimePrefix [8]byte
+
+// emsaPSSEncode and emsaPSSVerify take a hash constructor rather than a hash
+// instance and use separate instances for H and for the mask, so neither
+// computation depends on the other resetting the hash correctly.
+
+func emsaPSSEncode(mHash []byte, emBits int, salt []byte, newHash func() hash.Hash, o *options) ([]byte, error) {
+	return emsaPSSEncodeInto(make([]byte, (emBits+7)/8), mHash, emBits, salt, newHash, o)
+}
+
+// emsaPSSEncodeInto is like emsaPSSEncode but writes the encoded message to
+// em, which must be (emBits+7)/8 bytes long, and returns it.
+//
+// The steps of RFC 3447 quoted in emsaPSSEncodeInto and emsaPSSVerify assume
+// the one octet trailer 0xbc. With a trailer of tLen octets the code
+// subtracts tLen where the RFC subtracts the 1 that accounts for it.
+func emsaPSSEncodeInto(em []byte, mHash []byte, emBits int, salt []byte, newHash func() hash.Hash, o *options) ([]byte, error) {
+	hash := newHash()
 	hLen := hash.Size()
 	sLen := len(salt)
 	emLen := (emBits + 7) / 8
+	trailer := o.trailerField()
+	tLen := len(trailer)
 
 	// 1.  If the length of M is greater than the input limitation for the
 	//     hash function (2^61 - 1 octets for SHA-1), output "message too
@@ -25,18 +50,29 @@ func emsaPSSEncode(mHash []byte, emBits int, salt []byte, hash hash.Hash) ([]byt
 	// 2.  Let mHash = Hash(M), an octet string of length hLen.
 
 	if len(mHash) != hLen {
-		return nil, errors.New("crypto/rsa: input must be hashed message")
+		return nil, fmt.Errorf("crypto/rsa: input must be hashed message: got %d bytes, want %d", len(mHash), hLen)
+	}
+	if err := o.checkMGFHash(); err != nil {
+		return nil, err
 	}
 
 	// 3.  If emLen < hLen + sLen + 2, output "encoding error" and stop.
+	//
+	// A key that is too small for the hash even without a salt is reported
+	// apart from a salt that is too long for the key.
 
-	if emLen < hLen+sLen+2 {
+	if emLen < hLen+1+tLen {
 		return nil, errors.New("crypto/rsa: encoding error")
 	}
+	if maxSLen := emLen - hLen - 1 - tLen; sLen > maxSLen {
+		return nil, fmt.Errorf("crypto/rsa: salt length %d exceeds maximum %d for this key and hash", sLen, maxSLen)
+	}
 
-	em := make([]byte, emLen)
-	db := em[:emLen-sLen-hLen-2+1+sLen]
-	h := em[emLen-sLen-hLen-2+1+sLen : emLen-1]
+	for i := range em {
+		em[i] = 0
+	}
+	db := em[:emLen-hLen-tLen]
+	h := em[emLen-hLen-tLen : emLen-tLen]
 
 	// 4.  Generate a random octet string salt of length sLen; if sLen = 0,
 	//     then salt is the empty string.
@@ -49,14 +85,15 @@ func emsaPSSEncode(mHash []byte, emBits int, salt []byte, hash hash.Hash) ([]byt
 	//
 	// 6.  Let H = Hash(M'), an octet string of length hLen.
 
-	prefix := []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
-
-	hash.Write(prefix[0:8])
+	hash.Write(mPrimePrefix[:])
 	hash.Write(mHash)
 	hash.Write(salt)
 
 	h = hash.Sum(h[:0])
-	hash.Reset()
+	seed, err := o.mgfSeed(h)
+	if err != nil {
+		return nil, err
+	}
 
 	// 7.  Generate an octet string PS consisting of emLen - sLen - hLen - 2
 	//     zero octets.  The length of PS may be 0.
@@ -64,14 +101,14 @@ func emsaPSSEncode(mHash []byte, emBits int, salt []byte, hash hash.Hash) ([]byt
 	// 8.  Let DB = PS || 0x01 || salt; DB is an octet string of length
 	//     emLen - hLen - 1.
 
-	db[emLen-sLen-hLen-2] = 0x01
-	copy(db[emLen-sLen-hLen-1:], salt)
+	db[emLen-sLen-hLen-tLen-1] = 0x01
+	copy(db[emLen-sLen-hLen-tLen:], salt)
 
 	// 9.  Let dbMask = MGF(H, emLen - hLen - 1).
 	//
 	// 10. Let maskedDB = DB \xor dbMask.
 
-	mgf1XOR(db, hash, h)
+	o.mgf1XOR(db, newHash, seed)
 
 	// 11. Set the leftmost 8emLen - emBits bits of the leftmost octet in
 	//     maskedDB to zero.
@@ -79,66 +116,132 @@ func emsaPSSEncode(mHash []byte, emBits int, salt []byte, hash hash.Hash) ([]byt
 	db[0] &= (0xFF >> uint(8*emLen-emBits))
 
 	// 12. Let EM = maskedDB || H || 0xbc.
-	em[emLen-1] = 0xBC
+	copy(em[emLen-tLen:], trailer)
 
 	// 13. Output EM.
 	return em, nil
 }
 
-func emsaPSSVerify(mHash []byte, em []byte, emBits, sLen int, hash hash.Hash) error {
+func emsaPSSVerify(mHash []byte, em []byte, emBits, sLen int, newHash func() hash.Hash, o *options) error {
+	// The checks below that depend on em are collected in ok. They return
+	// as soon as one fails unless a constant time verification was asked
+	// for, in which case every step is carried out and ok is only looked at
+	// once at the end. The checks on lengths depend only on public
+	// parameters and always return early.
+	ok := 1
+	fail := func(cond int) bool {
+		ok &= cond
+		return ok == 0 && !o.constantTime
+	}
+
 	// 1.  If the length of M is greater than the input limitation for the
 	//     hash function (2^61 - 1 octets for SHA-1), output "inconsistent"
 	//     and stop.
 	//
 	// 2.  Let mHash = Hash(M), an octet string of length hLen.
+	hash := newHash()
 	hLen := hash.Size()
-	if hLen != len(mHash) {
+	if o.identityHashErr != nil {
+		return o.identityHashErr
+	}
+	if len(mHash) != o.mHashLen(hLen) {
 		return rsa.ErrVerification
 	}
+	if err := o.checkMGFHash(); err != nil {
+		return err
+	}
+	trailer := o.trailerField()
+	tLen := len(trailer)
+
+	auto := sLen == SaltLengthAuto
+	switch {
+	case auto:
+		sLen = 0
+	case sLen < 0:
+		return errNegativeSaltLength
+	case o.saltNotLongerThanHash && sLen > hLen:
+		return ErrSaltTooLong
+	}
 
 	// 3.  If emLen < hLen + sLen + 2, output "inconsistent" and stop.
+	//
+	// em must also be exactly emLen bytes long, so that every index below
+	// lies within it, whatever the caller passed as emBits.
 	emLen := (emBits + 7) / 8
-	if emLen < hLen+sLen+2 {
+	if emBits <= 0 || len(em) != emLen || emLen < hLen+sLen+1+tLen {
 		return rsa.ErrVerification
 	}
 
 	// 4.  If the rightmost octet of EM does not have hexadecimal value
 	//     0xbc, output "inconsistent" and stop.
-	if em[len(em)-1] != 0xBC {
+	if fail(subtle.ConstantTimeCompare(em[len(em)-tLen:], trailer)) {
 		return rsa.ErrVerification
 	}
 
 	// 5.  Let maskedDB be the leftmost emLen - hLen - 1 octets of EM, and
 	//     let H be the next hLen octets.
-	db := em[:emLen-hLen-1]
-	h := em[emLen-hLen-1 : len(em)-1]
+	db := em[:emLen-hLen-tLen]
+	h := em[emLen-hLen-tLen : len(em)-tLen]
+	// The length checks of step 3 make h exactly hLen octets long; make sure
+	// of it, since step 14 relies on it.
+	if len(h) != hLen {
+		return rsa.ErrVerification
+	}
+	seed, err := o.mgfSeed(h)
+	if err != nil {
+		return err
+	}
 
 	// 6.  If the leftmost 8emLen - emBits bits of the leftmost octet in
 	//     maskedDB are not all equal to zero, output "inconsistent" and
 	//     stop.
-	if em[0]&(0xFF<<uint(8-(8*emLen-emBits))) != 0 {
+	//
+	// The RFC checks maskedDB before it is unmasked, so a signature with
+	// these bits set is rejected before the mask is generated; doing so
+	// changes nothing about which signatures are accepted.
+	if fail(subtle.ConstantTimeByteEq(em[0]&(0xFF<<uint(8-(8*emLen-emBits))), 0)) {
 		return rsa.ErrVerification
 	}
 
 	// 7.  Let dbMask = MGF(H, emLen - hLen - 1).
 	//
 	// 8.  Let DB = maskedDB \xor dbMask.
-	mgf1XOR(db, hash, h)
+	o.mgf1XOR(db, newHash, seed)
 
 	// 9.  Set the leftmost 8emLen - emBits bits of the leftmost octet in DB
 	//     to zero.
 	db[0] &= (0xFF >> uint(8*emLen-emBits))
 
+	// The salt length is not known, so find it from the position of the
+	// 0x01 octet that ends the zero padding. DB is as long as the encoded
+	// message less H and the trailer, whose length is that of the message
+	// hash, whichever hash MGF1 uses.
+	if auto {
+		sep := 0
+		for sep < len(db) && db[sep] == 0 {
+			sep++
+		}
+		if sep == len(db) || db[sep] != 0x01 {
+			return rsa.ErrVerification
+		}
+		sLen = len(db) - sep - 1
+		if o.saltNotLongerThanHash && sLen > hLen {
+			return ErrSaltTooLong
+		}
+	}
+
 	// 10. If the emLen - hLen - sLen - 2 leftmost octets of DB are not zero
 	//     or if the octet at position emLen - hLen - sLen - 1 (the leftmost
 	//     position is "position 1") does not have hexadecimal value 0x01,
 	//     output "inconsistent" and stop.
-	for _, e := range db[:emLen-hLen-sLen-2] {
-		if e != 0x00 {
-			return rsa.ErrVerification
-		}
+	var ps byte
+	for _, e := range db[:emLen-hLen-sLen-tLen-1] {
+		ps |= e
 	}
-	if db[emLen-hLen-sLen-2] != 0x01 {
+	if fail(subtle.ConstantTimeByteEq(ps, 0x00)) {
+		return rsa.ErrVerification
+	}
+	if fail(subtle.ConstantTimeByteEq(db[emLen-hLen-sLen-tLen-1], 0x01)) {
 		return rsa.ErrVerification
 	}
 
@@ -151,8 +254,10 @@ func emsaPSSVerify(mHash []byte, em []byte, emBits, sLen int, hash hash.Hash) er
 	//     initial zero octets.
 	//
 	// 13. Let H' = Hash(M'), an octet string of length hLen.
-	prefix := []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
-	hash.Write(prefix)
+	//
+	// hash is reset first, as it may be the instance MGF1 has just used.
+	hash.Reset()
+	hash.Write(mPrimePrefix[:])
 	hash.Write(mHash)
 	hash.Write(salt)
 
@@ -160,10 +265,11 @@ func emsaPSSVerify(mHash []byte, em []byte, emBits, sLen int, hash hash.Hash) er
 	h0 = hash.Sum(h0[:0])
 
 	// 14. If H = H', output "consistent." Otherwise, output "inconsistent."
-	for i, e := range h0 {
-		if e != h[i] {
-			return rsa.ErrVerification
-		}
+	if fail(o.compareHash(h0, h)) || ok != 1 {
+		return rsa.ErrVerification
+	}
+	if o.saltLengthFound != nil {
+		o.saltLengthFound(sLen)
 	}
 	return nil
 }
@@ -171,38 +277,300 @@ func emsaPSSVerify(mHash []byte, em []byte, emBits, sLen int, hash hash.Hash) er
 // SignPSS calculates the signature of hashed using RSASSA-PSS from RFC 3447 Section 8.1.
 // Note that hashed must be the result of hashing the input message using the given hash funcion.
 // salt is a random sequence of bytes whose length will be later used to verify the signature.
-func SignPSS(rand io.Reader, priv *rsa.PrivateKey, hash crypto.Hash, hashed []byte, salt []byte) (s []byte, err error) {
-	em, err := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, hash.New())
+// rand is used for RSA blinding and must not be nil; see WithBlindingFailurePolicy for what happens when it fails
+// and WithoutBlinding for signing without it.
+//
+// Signing only reads priv, so one key may be used by any number of goroutines
+// at once. priv.Precompute, however, writes to priv.Precomputed and has to
+// be called before the key is shared; keys from rsa.GenerateKey and the
+// x509 parsers are already precomputed. A key without precomputed values is
+// used without the CRT.
+//
+// A key needs only N, E and D; without Primes the signature is computed as
+// m^d mod n. Blinding needs E, so a key that lacks it can only sign with
+// WithoutBlinding.
+func SignPSS(rand io.Reader, priv *rsa.PrivateKey, hash crypto.Hash, hashed []byte, salt []byte, opts ...Option) (s []byte, err error) {
+	return signPSS(rand, priv, hash, hashed, salt, newOptions(opts))
+}
+
+func signPSS(rand io.Reader, priv *rsa.PrivateKey, hash crypto.Hash, hashed []byte, salt []byte, o *options) (s []byte, err error) {
+	return signPSSInto(nil, rand, priv, cryptoHashFunc(hash), hashed, salt, o)
+}
+
+// hashFunc describes the hash function of a signature: how to make one, the
+// size of its output and, if it has one, its name for error messages.
+type hashFunc struct {
+	new  func() hash.Hash
+	size int
+	name string
+}
+
+func cryptoHashFunc(hash crypto.Hash) hashFunc {
+	return hashFunc{new: hash.New, size: hash.Size(), name: hash.String()}
+}
+
+// check returns a descriptive error if hashed cannot be the output of h.
+func (h hashFunc) check(hashed []byte) error {
+	if len(hashed) == h.size {
+		return nil
+	}
+	if h.name != "" {
+		return fmt.Errorf("crypto/rsa: input must be hashed message: got %d bytes, want %d for %s", len(hashed), h.size, h.name)
+	}
+	return fmt.Errorf("crypto/rsa: input must be hashed message: got %d bytes, want %d", len(hashed), h.size)
+}
+
+// SignPSSWith is like SignPSS but takes a constructor for the hash function
+// and the size of its output instead of a crypto.Hash, so that hash
+// functions that are not registered with package crypto can be used.
+func SignPSSWith(rand io.Reader, priv *rsa.PrivateKey, newHash func() hash.Hash, hashSize int, hashed []byte, salt []byte, opts ...Option) ([]byte, error) {
+	if err := checkHashSize(newHash, hashSize); err != nil {
+		return nil, err
+	}
+	return signPSSInto(nil, rand, priv, hashFunc{new: newHash, size: hashSize}, hashed, salt, newOptions(opts))
+}
+
+// checkHashSize checks that newHash makes hashes of the given size.
+func checkHashSize(newHash func() hash.Hash, hashSize int) error {
+	if hashSize <= 0 {
+		return fmt.Errorf("crypto/rsa: hash size %d is not positive", hashSize)
+	}
+	if size := newHash().Size(); size != hashSize {
+		return fmt.Errorf("crypto/rsa: hash size is %d, not %d", size, hashSize)
+	}
+	return nil
+}
+
+// SignPSSInto is like SignPSS but writes the signature to dst and returns it,
+// so that a tight signing loop can reuse one buffer. Only the capacity of
+// dst matters: unlike append, the signature always starts at dst[0], so
+// passing buf[:0] or buf[:k] gives the same result. If the capacity is
+// smaller than the size of the modulus a new slice is allocated instead.
+// The encoded message is built in the same buffer, so dst must not overlap
+// hashed or salt.
+func SignPSSInto(dst []byte, rand io.Reader, priv *rsa.PrivateKey, hash crypto.Hash, hashed []byte, salt []byte, opts ...Option) ([]byte, error) {
+	return signPSSInto(dst, rand, priv, cryptoHashFunc(hash), hashed, salt, newOptions(opts))
+}
+
+func signPSSInto(dst []byte, rand io.Reader, priv *rsa.PrivateKey, h hashFunc, hashed []byte, salt []byte, o *options) (s []byte, err error) {
+	if rand == nil && !o.noBlinding {
+		return nil, errNilRandom
+	}
+	o.startPhase(PhaseHashCheck)
+	err = h.check(hashed)
+	o.endPhase(PhaseHashCheck)
 	if err != nil {
-		return
+		return nil, err
+	}
+	o.warnIfRawMessage(hashed)
+
+	k := (priv.N.BitLen() + 7) / 8
+	if cap(dst) < k {
+		dst = make([]byte, k)
+	}
+	s = dst[:k]
+	emBits := priv.N.BitLen() - 1
+	emLen := (emBits + 7) / 8
+	o.startPhase(PhaseEncode)
+	em, err := emsaPSSEncodeInto(s[k-emLen:], hashed, emBits, salt, h.new, o)
+	o.endPhase(PhaseEncode)
+	if err != nil {
+		return nil, err
 	}
 	m := new(big.Int).SetBytes(em)
-	c, err := decrypt(rand, priv, m)
+	var start, end time.Time
+	if o.costHook != nil {
+		start = time.Now()
+	}
+	c, err := decrypt(rand, priv, m, o)
 	if err != nil {
-		return
+		return nil, err
+	}
+	// The cost covers the private key operation only, not the check below.
+	if o.costHook != nil {
+		end = time.Now()
 	}
-	s = make([]byte, (priv.N.BitLen()+7)/8)
+	if o.selfVerify {
+		if err = o.checkSignature(priv, m, c); err != nil {
+			return nil, err
+		}
+	}
+	if o.costHook != nil {
+		o.costHook(SignCost{
+			Start:       start,
+			End:         end,
+			ModulusBits: priv.N.BitLen(),
+			CRT:         hasCRTValues(priv),
+		})
+	}
+	o.startPhase(PhasePadding)
 	copyWithLeftPad(s, c.Bytes())
+	o.endPhase(PhasePadding)
 	return
 }
 
+// SaltLengthAuto can be passed as the salt length to VerifyPSS and the other
+// verification functions to accept a signature with any salt length, which
+// is then recovered from the encoded message.
+const SaltLengthAuto = -1
+
+// ErrSaltTooLong is returned by verification with
+// WithRejectSaltLongerThanHash if the salt is longer than the hash.
+var ErrSaltTooLong = errors.New("crypto/rsa: salt length exceeds hash length")
+
+// ErrEncodedMessageTooLong is the cause of a verification failure in which
+// the public key operation on the signature gives an integer too large for
+// an encoded message of the key, which usually means that the signature was
+// made with another key or has been corrupted. Errors with this cause also
+// satisfy errors.Is(err, rsa.ErrVerification).
+var ErrEncodedMessageTooLong = errors.New("crypto/rsa: verification error: encoded message too long")
+
+// verificationError is a verification failure with a more specific cause.
+type verificationError struct {
+	cause error
+}
+
+func (e *verificationError) Error() string { return e.cause.Error() }
+
+func (e *verificationError) Unwrap() []error { return []error{rsa.ErrVerification, e.cause} }
+
 // VerifyPSS verifies an RSASSA-PSS signature.
 // hashed is the result of hashing the input message using the given hash function and sig is the signature.
 // A valid signature is indicated by returning a nil error.
-// sLen is number of bytes of the salt used to sign the message.
-func VerifyPSS(pub *rsa.PublicKey, hash crypto.Hash, hashed []byte, sig []byte, sLen int) error {
-	s := new(big.Int).SetBytes(sig)
-	m := encrypt(new(big.Int), pub, s)
-	emBits := pub.N.BitLen() - 1
-	emLen := (emBits + 7) / 8
-	if emLen < len(m.Bytes()) {
-		return rsa.ErrVerification
+// sLen is number of bytes of the salt used to sign the message, or SaltLengthAuto.
+// Verification involves only the public key and never reads randomness, so
+// it needs no blinding. Keys with a modulus larger than
+// DefaultMaxModulusBits are rejected; see WithMaxModulusBits.
+func VerifyPSS(pub *rsa.PublicKey, hash crypto.Hash, hashed []byte, sig []byte, sLen int, opts ...Option) error {
+	return verifyPSS(pub, hash.New, hashed, sig, sLen, new(verifyScratch), newOptions(opts))
+}
+
+// VerifyPSSWith is like VerifyPSS but takes a constructor for the hash
+// function and the size of its output instead of a crypto.Hash.
+func VerifyPSSWith(pub *rsa.PublicKey, newHash func() hash.Hash, hashSize int, hashed []byte, sig []byte, sLen int, opts ...Option) error {
+	if err := checkHashSize(newHash, hashSize); err != nil {
+		return err
 	}
-	em := make([]byte, emLen)
-	copyWithLeftPad(em, m.Bytes())
-	err := emsaPSSVerify(hashed, em, pub.N.BitLen()-1, sLen, hash.New())
+	return verifyPSS(pub, newHash, hashed, sig, sLen, new(verifyScratch), newOptions(opts))
+}
+
+// VerifyPSSBool is like VerifyPSS but only reports whether sig is a valid
+// signature, for bulk verification that has no use for the reason.
+func VerifyPSSBool(pub *rsa.PublicKey, hash crypto.Hash, hashed []byte, sig []byte, sLen int, opts ...Option) bool {
+	return verifyPSS(pub, hash.New, hashed, sig, sLen, new(verifyScratch), newOptions(opts)) == nil
+}
+
+// ErrDigestMismatch is returned by VerifyPSSStrict when the digest passed
+// in is not the hash of the message.
+var ErrDigestMismatch = errors.New("crypto/rsa: digest is not the hash of the message")
+
+// VerifyPSSStrict is like VerifyPSS but also takes the message that hashed
+// is the digest of. It hashes message again and fails with
+// ErrDigestMismatch unless the result is hashed, which catches a caller
+// passing the digest of some other message or one made with another hash
+// function. It is meant for high value verifications where hashing the
+// message twice is cheap insurance.
+func VerifyPSSStrict(pub *rsa.PublicKey, hash crypto.Hash, message, hashed []byte, sig []byte, sLen int, opts ...Option) error {
+	h := hash.New()
+	h.Write(message)
+	if subtle.ConstantTimeCompare(h.Sum(nil), hashed) != 1 {
+		return ErrDigestMismatch
+	}
+	return VerifyPSS(pub, hash, hashed, sig, sLen, opts...)
+}
+
+// VerifyPSSBigExponent is like VerifyPSS but takes the modulus n and the
+// public exponent e of the key as big integers, for keys whose exponent does
+// not fit in the int of rsa.PublicKey.E, which has only 32 bits on some
+// platforms. Such exponents are valid but rare; crypto/rsa itself refuses
+// to use them.
+func VerifyPSSBigExponent(n, e *big.Int, hash crypto.Hash, hashed []byte, sig []byte, sLen int, opts ...Option) error {
+	return verifyPSSExp(n, e, hash.New, hashed, sig, sLen, new(verifyScratch), newOptions(opts))
+}
+
+// DecodeSignature carries out the first, expensive, half of VerifyPSS: it
+// checks the length of sig, raises it to the public exponent and returns
+// the encoded message. VerifyDecodedPSS completes the verification, so that
+// the two halves can be scheduled on different workers. The encoded message
+// is pub.N.BitLen()-1 bits long.
+func DecodeSignature(pub *rsa.PublicKey, sig []byte, opts ...Option) (em []byte, err error) {
+	sc := new(verifyScratch)
+	return decodePSS(pub.N, sc.e.SetInt64(int64(pub.E)), sig, sc, newOptions(opts))
+}
+
+// VerifyDecodedPSS carries out the second half of VerifyPSS on an encoded
+// message returned by DecodeSignature, with emBits the number of bits of
+// the modulus less one. It unmasks em in place.
+func VerifyDecodedPSS(em []byte, hash crypto.Hash, hashed []byte, emBits, sLen int, opts ...Option) error {
+	return emsaPSSVerify(hashed, em, emBits, sLen, hash.New, newOptions(opts))
+}
+
+// verifyScratch holds the buffers used by a single verification so that
+// they can be reused.
+type verifyScratch struct {
+	s, m, e big.Int
+	em      []byte
+}
+
+func verifyPSS(pub *rsa.PublicKey, newHash func() hash.Hash, hashed []byte, sig []byte, sLen int, sc *verifyScratch, o *options) error {
+	return verifyPSSExp(pub.N, sc.e.SetInt64(int64(pub.E)), newHash, hashed, sig, sLen, sc, o)
+}
+
+var errNonPositiveExponent = errors.New("crypto/rsa: public exponent must be positive")
+
+func verifyPSSExp(n, e *big.Int, newHash func() hash.Hash, hashed []byte, sig []byte, sLen int, sc *verifyScratch, o *options) error {
+	o.warnIfRawMessage(hashed)
+	em, err := decodePSS(n, e, sig, sc, o)
 	if err != nil {
 		return err
 	}
-	return nil
+	return emsaPSSVerify(hashed, em, n.BitLen()-1, sLen, newHash, o)
+}
+
+// decodePSS carries out the RSA part of verification and returns the
+// encoded message, which is held in sc.
+func decodePSS(n, e *big.Int, sig []byte, sc *verifyScratch, o *options) ([]byte, error) {
+	// A negative exponent would make Exp compute a modular inverse.
+	if e.Sign() <= 0 {
+		return nil, errNonPositiveExponent
+	}
+	if err := o.checkModulusSize(n); err != nil {
+		return nil, err
+	}
+	// The signature must be exactly as long as the modulus. A signature one
+	// byte short may be accepted as if it were left-padded with a zero;
+	// SetBytes gives the same integer either way.
+	k := (n.BitLen() + 7) / 8
+	if len(sig) != k && !(o.missingLeadingZero && len(sig) == k-1) {
+		return nil, rsa.ErrVerification
+	}
+	// RSAVP1 step 1: the signature representative must be below the
+	// modulus, or s + n would be accepted as well as s.
+	s := sc.s.SetBytes(sig)
+	if s.Cmp(n) >= 0 {
+		return nil, rsa.ErrVerification
+	}
+	m := sc.m.Exp(s, e, n)
+	emBits := n.BitLen() - 1
+	emLen := (emBits + 7) / 8
+	if emLen < (m.BitLen()+7)/8 {
+		return nil, &verificationError{ErrEncodedMessageTooLong}
+	}
+	if cap(sc.em) < emLen {
+		sc.em = make([]byte, emLen)
+	}
+	// FillBytes writes all emLen bytes, the leading zeros included.
+	return m.FillBytes(sc.em[:emLen]), nil
+}
+
+var errNilRandom = errors.New("crypto/rsa: nil random source; use WithoutBlinding to sign without blinding and WithSaltSource for the salt")
+
+// SignPSSFixed is like SignPSS but does not use RSA blinding, so that the
+// signature depends only on its inputs. It exists to reproduce known-answer
+// test vectors, which fix the salt; SignPSS computes the same signature for
+// the same salt, but SignPSSFixed needs no random source at all.
+// Because the private key operation is not blinded it may leak information
+// about the key through timing, and must not be used for production signing.
+func SignPSSFixed(priv *rsa.PrivateKey, hash crypto.Hash, hashed []byte, salt []byte) ([]byte, error) {
+	return signPSS(nil, priv, hash, hashed, salt, newOptions([]Option{WithoutBlinding()}))
 }
@@ -0,0 +1,1025 @@
+package pss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"hash/fnv"
+	"io"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var (
+	testKeyOnce sync.Once
+	testKeyPriv *rsa.PrivateKey
+	testKeyErr  error
+)
+
+// testKey returns a 2048 bit key shared by the tests in this package.
+func testKey(t testing.TB) *rsa.PrivateKey {
+	testKeyOnce.Do(func() {
+		testKeyPriv, testKeyErr = rsa.GenerateKey(rand.Reader, 2048)
+	})
+	if testKeyErr != nil {
+		t.Fatal(testKeyErr)
+	}
+	return testKeyPriv
+}
+
+func TestSignPSSByteBoundaries(t *testing.T) {
+	hashed := sha256.Sum256([]byte("byte boundary"))
+	salt := make([]byte, sha256.Size)
+	for _, bits := range []int{2047, 2048, 2049} {
+		priv, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			t.Fatalf("%d bits: %v", bits, err)
+		}
+		if priv.N.BitLen() != bits {
+			t.Fatalf("got a %d bit key, want %d", priv.N.BitLen(), bits)
+		}
+		if _, err := rand.Read(salt); err != nil {
+			t.Fatal(err)
+		}
+		sig, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], salt)
+		if err != nil {
+			t.Errorf("%d bits: sign error: %v", bits, err)
+			continue
+		}
+		if len(sig) != (bits+7)/8 {
+			t.Errorf("%d bits: signature is %d bytes, want %d", bits, len(sig), (bits+7)/8)
+		}
+		err = VerifyPSS(&priv.PublicKey, crypto.SHA256, hashed[:], sig, len(salt))
+		if err != nil {
+			t.Errorf("%d bits: verify error: %v", bits, err)
+		}
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read(p []byte) (int, error) {
+	return 0, errors.New("random source unavailable")
+}
+
+func TestSignPSSBlindingFailurePolicy(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("blinding"))
+	salt := []byte("some salt")
+
+	want, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = SignPSS(errReader{}, priv, crypto.SHA256, hashed[:], salt)
+	if err == nil {
+		t.Errorf("signing with a broken random source succeeded under FailClosed")
+	}
+
+	var buf bytes.Buffer
+	sig, err := SignPSS(errReader{}, priv, crypto.SHA256, hashed[:], salt,
+		WithBlindingFailurePolicy(FallBackUnblinded), WithLogger(log.New(&buf, "", 0)))
+	if err != nil {
+		t.Fatalf("FallBackUnblinded: %v", err)
+	}
+	if !bytes.Equal(sig, want) {
+		t.Errorf("unblinded signature differs from blinded one")
+	}
+	if !strings.Contains(buf.String(), "blinding failed") {
+		t.Errorf("no warning logged, got %q", buf.String())
+	}
+}
+
+func TestSignPSSInconsistentPrimes(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("inconsistent key"))
+	salt := []byte("salt")
+	want, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, primes := range [][]*big.Int{nil, priv.Primes[:1]} {
+		bad := &rsa.PrivateKey{
+			PublicKey:   priv.PublicKey,
+			D:           priv.D,
+			Primes:      primes,
+			Precomputed: priv.Precomputed,
+		}
+		sig, err := SignPSS(rand.Reader, bad, crypto.SHA256, hashed[:], salt)
+		if err != nil {
+			t.Errorf("%d primes: %v", len(primes), err)
+			continue
+		}
+		if !bytes.Equal(sig, want) {
+			t.Errorf("%d primes: wrong signature", len(primes))
+		}
+	}
+}
+
+func TestVerifyPSSConstantTime(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("constant time"))
+	sig, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], []byte("salt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyPSS(&priv.PublicKey, crypto.SHA256, hashed[:], sig, 4, WithConstantTimeVerify()); err != nil {
+		t.Errorf("valid signature: %v", err)
+	}
+	sig[10] ^= 0x01
+	if err := VerifyPSS(&priv.PublicKey, crypto.SHA256, hashed[:], sig, 4, WithConstantTimeVerify()); err != rsa.ErrVerification {
+		t.Errorf("corrupted signature: got %v, want rsa.ErrVerification", err)
+	}
+}
+
+func TestWithHashCompare(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	hashed := sha256.Sum256([]byte("custom comparison"))
+	sig, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], []byte("salt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := sha256.Sum256([]byte("other message"))
+	for _, test := range []struct {
+		name   string
+		hashed []byte
+		sLen   int
+		valid  bool
+	}{
+		{"valid", hashed[:], 4, true},
+		{"SaltLengthAuto", hashed[:], SaltLengthAuto, true},
+		{"other message", other[:], 4, false},
+	} {
+		calls := 0
+		compare := WithHashCompare(func(a, b []byte) int {
+			calls++
+			return subtle.ConstantTimeCompare(a, b)
+		})
+		err := VerifyPSS(pub, crypto.SHA256, test.hashed, sig, test.sLen, compare)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: got %v", test.name, err)
+		}
+		if calls != 1 {
+			t.Errorf("%s: comparison called %d times, want 1", test.name, calls)
+		}
+	}
+
+	reject := WithHashCompare(func(a, b []byte) int { return 0 })
+	if err := VerifyPSS(pub, crypto.SHA256, hashed[:], sig, 4, reject); err != rsa.ErrVerification {
+		t.Errorf("comparison reporting a mismatch: got %v, want rsa.ErrVerification", err)
+	}
+}
+
+func TestSignPSSInto(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("into"))
+	salt := []byte("salt")
+	want, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the capacity of dst counts, not its length.
+	buf := make([]byte, 0, 512)
+	for i, dst := range [][]byte{buf, buf, buf[:256], buf[:100], buf[:512]} {
+		sig, err := SignPSSInto(dst, rand.Reader, priv, crypto.SHA256, hashed[:], salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sig, want) {
+			t.Errorf("len(dst) = %d: wrong signature", len(dst))
+		}
+		if &sig[0] != &buf[:1][0] {
+			t.Errorf("iteration %d: buffer with enough capacity was not reused", i)
+		}
+	}
+
+	small := make([]byte, 10)
+	sig, err := SignPSSInto(small, rand.Reader, priv, crypto.SHA256, hashed[:], salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sig, want) || len(sig) != 256 {
+		t.Errorf("grown buffer holds the wrong signature")
+	}
+
+	// On failure no part of the buffer, which may hold the encoded
+	// message, is returned.
+	if sig, err := SignPSSInto(buf, rand.Reader, priv, crypto.SHA256, hashed[:], make([]byte, 1000)); err == nil || sig != nil {
+		t.Errorf("encoding failure: got %d bytes, %v", len(sig), err)
+	}
+	if sig, err := SignPSSInto(buf, errReader{}, priv, crypto.SHA256, hashed[:], salt); err == nil || sig != nil {
+		t.Errorf("blinding failure: got %d bytes, %v", len(sig), err)
+	}
+}
+
+func TestSignPSSUnhashedInput(t *testing.T) {
+	priv := testKey(t)
+	_, err := SignPSS(rand.Reader, priv, crypto.SHA256, []byte("raw message"), nil)
+	if err == nil {
+		t.Fatal("signing an unhashed message succeeded")
+	}
+	if want := "got 11 bytes, want 32 for SHA-256"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not contain %q", err, want)
+	}
+}
+
+func TestSignPSSWith(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("hash constructor"))
+	salt := []byte("salt")
+	sig, err := SignPSSWith(rand.Reader, priv, sha256.New, sha256.Size, hashed[:], salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sig, want) {
+		t.Errorf("SignPSSWith and SignPSS disagree")
+	}
+	if err := VerifyPSSWith(&priv.PublicKey, sha256.New, sha256.Size, hashed[:], sig, len(salt)); err != nil {
+		t.Errorf("VerifyPSSWith: %v", err)
+	}
+	if _, err := SignPSSWith(rand.Reader, priv, sha256.New, 20, hashed[:], salt); err == nil {
+		t.Errorf("SignPSSWith accepted a wrong hash size")
+	}
+	if err := VerifyPSSWith(&priv.PublicKey, sha256.New, 20, hashed[:], sig, len(salt)); err == nil {
+		t.Errorf("VerifyPSSWith accepted a wrong hash size")
+	}
+}
+
+func TestVerifyPSSMissingLeadingZero(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("leading zero"))
+	var sig []byte
+	for i := 0; i < 5000; i++ {
+		s, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s[0] == 0 {
+			sig = s
+			break
+		}
+	}
+	if sig == nil {
+		t.Skip("no signature with a leading zero byte found")
+	}
+	pub := &priv.PublicKey
+	if err := VerifyPSS(pub, crypto.SHA256, hashed[:], sig, sha256.Size); err != nil {
+		t.Fatalf("full length signature: %v", err)
+	}
+	short := sig[1:]
+	if err := VerifyPSS(pub, crypto.SHA256, hashed[:], short, sha256.Size); err != rsa.ErrVerification {
+		t.Errorf("short signature: got %v, want rsa.ErrVerification", err)
+	}
+	if err := VerifyPSS(pub, crypto.SHA256, hashed[:], short, sha256.Size, WithTolerateMissingLeadingZero()); err != nil {
+		t.Errorf("short signature with tolerance: %v", err)
+	}
+	if err := VerifyPSS(pub, crypto.SHA256, hashed[:], short[1:], sha256.Size, WithTolerateMissingLeadingZero()); err != rsa.ErrVerification {
+		t.Errorf("two bytes short: got %v, want rsa.ErrVerification", err)
+	}
+}
+
+func TestSignPSSTrailer(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	hashed := sha256.Sum256([]byte("trailer"))
+	// IEEE 1363 trailer for SHA-256: the hash identifier 0x34 and 0xcc.
+	trailer := []byte{0x34, 0xCC}
+	sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size, WithTrailer(trailer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := new(big.Int).Exp(new(big.Int).SetBytes(sig), big.NewInt(int64(pub.E)), pub.N)
+	if em := m.Bytes(); !bytes.HasSuffix(em, trailer) {
+		t.Errorf("encoded message ends in %x, want %x", em[len(em)-2:], trailer)
+	}
+	if err := VerifyPSS(pub, crypto.SHA256, hashed[:], sig, sha256.Size, WithTrailer(trailer)); err != nil {
+		t.Errorf("verify with the trailer: %v", err)
+	}
+	if err := VerifyPSS(pub, crypto.SHA256, hashed[:], sig, sha256.Size); err != rsa.ErrVerification {
+		t.Errorf("verify with the default trailer: got %v, want rsa.ErrVerification", err)
+	}
+	reused := append([]byte(nil), trailer...)
+	withTrailer := WithTrailer(reused)
+	reused[0] = 0x33
+	if err := VerifyPSS(pub, crypto.SHA256, hashed[:], sig, sha256.Size, withTrailer); err != nil {
+		t.Errorf("trailer changed after WithTrailer: %v", err)
+	}
+
+	pssOpts := &PSSOptions{Hash: crypto.SHA256, SaltLength: sha256.Size, Trailer: trailer}
+	if err := VerifyPSSWithOpts(pub, hashed[:], sig, pssOpts); err != nil {
+		t.Errorf("VerifyPSSWithOpts with the trailer: %v", err)
+	}
+	sig, err = SignPSSWithOpts(rand.Reader, priv, hashed[:], PS256(), WithTrailer(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rsa.VerifyPSS(pub, crypto.SHA256, hashed[:], sig, nil); err != nil {
+		t.Errorf("crypto/rsa rejects a signature with the default trailer: %v", err)
+	}
+}
+
+func TestSignPSSLargeModulus(t *testing.T) {
+	if testing.Short() {
+		t.Skip("generating an 8192 bit key is slow")
+	}
+	priv, err := rsa.GenerateKey(rand.Reader, 8192)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashed := sha512.Sum512([]byte("large modulus"))
+	for _, saltLen := range []int{sha512.Size, DefaultSaltLength(&priv.PublicKey, crypto.SHA512)} {
+		sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA512, hashed[:], saltLen)
+		if err != nil {
+			t.Fatalf("salt length %d: %v", saltLen, err)
+		}
+		if len(sig) != 1024 {
+			t.Errorf("salt length %d: signature is %d bytes, want 1024", saltLen, len(sig))
+		}
+		if err := VerifyPSS(&priv.PublicKey, crypto.SHA512, hashed[:], sig, saltLen); err != nil {
+			t.Errorf("salt length %d: %v", saltLen, err)
+		}
+		if err := rsa.VerifyPSS(&priv.PublicKey, crypto.SHA512, hashed[:], sig, &rsa.PSSOptions{SaltLength: saltLen}); err != nil {
+			t.Errorf("salt length %d: crypto/rsa rejects the signature: %v", saltLen, err)
+		}
+	}
+}
+
+func TestVerifyPSSSaltLengthAuto(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	hashed := sha256.Sum256([]byte("salt length"))
+	maxSalt := DefaultSaltLength(pub, crypto.SHA256)
+	for _, saltLen := range []int{0, 1, sha256.Size, maxSalt} {
+		sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], saltLen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyPSS(pub, crypto.SHA256, hashed[:], sig, SaltLengthAuto); err != nil {
+			t.Errorf("salt length %d: auto: %v", saltLen, err)
+		}
+		err = VerifyPSS(pub, crypto.SHA256, hashed[:], sig, SaltLengthAuto, WithRejectSaltLongerThanHash())
+		if saltLen > sha256.Size && err != ErrSaltTooLong {
+			t.Errorf("salt length %d: auto with limit: got %v, want ErrSaltTooLong", saltLen, err)
+		}
+		if saltLen <= sha256.Size && err != nil {
+			t.Errorf("salt length %d: auto with limit: %v", saltLen, err)
+		}
+		err = VerifyPSS(pub, crypto.SHA256, hashed[:], sig, saltLen, WithRejectSaltLongerThanHash())
+		if saltLen > sha256.Size && err != ErrSaltTooLong {
+			t.Errorf("salt length %d: with limit: got %v, want ErrSaltTooLong", saltLen, err)
+		}
+		if saltLen <= sha256.Size && err != nil {
+			t.Errorf("salt length %d: with limit: %v", saltLen, err)
+		}
+		sig[len(sig)-1] ^= 1
+		if err := VerifyPSS(pub, crypto.SHA256, hashed[:], sig, SaltLengthAuto); err != rsa.ErrVerification {
+			t.Errorf("salt length %d: auto on a bad signature: got %v, want rsa.ErrVerification", saltLen, err)
+		}
+	}
+	if err := VerifyPSS(pub, crypto.SHA256, hashed[:], make([]byte, 256), -2); err == nil {
+		t.Errorf("negative salt length accepted")
+	}
+}
+
+// TestSignPSSConcurrent is meant to be run with -race.
+func TestSignPSSConcurrent(t *testing.T) {
+	hashed := sha256.Sum256([]byte("concurrent"))
+	for name, priv := range map[string]*rsa.PrivateKey{
+		"precomputed":     testKey(t),
+		"not precomputed": selfTestKey(),
+	} {
+		const goroutines = 16
+		var wg sync.WaitGroup
+		errs := make(chan error, goroutines)
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 4; j++ {
+					sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size)
+					if err == nil {
+						err = VerifyPSS(&priv.PublicKey, crypto.SHA256, hashed[:], sig, sha256.Size)
+					}
+					if err != nil {
+						errs <- err
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+// oddBlockHash is SHA-512/256 with a block size that no SHA-2 hash has and
+// without state marshaling.
+type oddBlockHash struct {
+	hash.Hash
+}
+
+func (oddBlockHash) BlockSize() int { return 7 }
+
+// TestSignPSSWithUnregisteredHash signs with hash functions that have no
+// crypto.Hash value, to check that nothing depends on the registry or on the
+// properties of the SHA family.
+func TestSignPSSWithUnregisteredHash(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	tests := []struct {
+		name    string
+		newHash func() hash.Hash
+		size    int
+	}{
+		{"FNV-128a", func() hash.Hash { return fnv.New128a() }, 16},
+		{"FNV-64", func() hash.Hash { return fnv.New64() }, 8},
+		{"odd block size", func() hash.Hash { return oddBlockHash{sha512.New512_256()} }, 32},
+		{"SHA3-256", func() hash.Hash { return sha3.New256() }, 32},
+	}
+	for _, test := range tests {
+		h := test.newHash()
+		h.Write([]byte("unregistered hash"))
+		hashed := h.Sum(nil)
+		for _, saltLen := range []int{0, test.size, 100} {
+			salt := make([]byte, saltLen)
+			rand.Read(salt)
+			sig, err := SignPSSWith(rand.Reader, priv, test.newHash, test.size, hashed, salt)
+			if err != nil {
+				t.Fatalf("%s, salt length %d: %v", test.name, saltLen, err)
+			}
+			if err := VerifyPSSWith(pub, test.newHash, test.size, hashed, sig, saltLen); err != nil {
+				t.Errorf("%s, salt length %d: %v", test.name, saltLen, err)
+			}
+			if err := VerifyPSSWith(pub, test.newHash, test.size, hashed, sig, SaltLengthAuto); err != nil {
+				t.Errorf("%s, salt length %d: auto: %v", test.name, saltLen, err)
+			}
+			hashed[0] ^= 1
+			if err := VerifyPSSWith(pub, test.newHash, test.size, hashed, sig, saltLen); err != rsa.ErrVerification {
+				t.Errorf("%s, salt length %d: wrong digest: got %v, want rsa.ErrVerification", test.name, saltLen, err)
+			}
+			hashed[0] ^= 1
+		}
+	}
+}
+
+func TestVerifyPSSEncodedMessageTooLong(t *testing.T) {
+	// With a modulus of 8n+1 bits the encoded message is a byte shorter
+	// than the modulus, so there are integers below N that do not fit.
+	priv, err := rsa.GenerateKey(rand.Reader, 1025)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := new(big.Int).Sub(priv.N, big.NewInt(1))
+	sig := make([]byte, (priv.N.BitLen()+7)/8)
+	new(big.Int).Exp(m, priv.D, priv.N).FillBytes(sig)
+	hashed := sha256.Sum256([]byte("too long"))
+	err = VerifyPSS(&priv.PublicKey, crypto.SHA256, hashed[:], sig, sha256.Size)
+	if !errors.Is(err, ErrEncodedMessageTooLong) {
+		t.Errorf("got %v, want ErrEncodedMessageTooLong", err)
+	}
+	if !errors.Is(err, rsa.ErrVerification) {
+		t.Errorf("%v does not match rsa.ErrVerification", err)
+	}
+	sig, err = SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashed[0] ^= 1
+	err = VerifyPSS(&priv.PublicKey, crypto.SHA256, hashed[:], sig, sha256.Size)
+	if err != rsa.ErrVerification {
+		t.Errorf("wrong digest: got %v, want rsa.ErrVerification", err)
+	}
+}
+
+// An all-zero digest is as valid as any other. Together with an all-zero
+// salt the data block is zero apart from the 0x01 separator and M' is all
+// zeros, which must not get confused with padding.
+func TestSignPSSZeroDigest(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	zero := make([]byte, sha256.Size)
+	other := make([]byte, sha256.Size)
+	other[len(other)-1] = 1
+	for _, salt := range [][]byte{nil, make([]byte, sha256.Size), []byte("salt")} {
+		sig, err := SignPSS(rand.Reader, priv, crypto.SHA256, zero, salt)
+		if err != nil {
+			t.Fatalf("salt %x: %v", salt, err)
+		}
+		for _, sLen := range []int{len(salt), SaltLengthAuto} {
+			if err := VerifyPSS(pub, crypto.SHA256, zero, sig, sLen); err != nil {
+				t.Errorf("salt %x, sLen %d: %v", salt, sLen, err)
+			}
+			if err := VerifyPSS(pub, crypto.SHA256, other, sig, sLen); err != rsa.ErrVerification {
+				t.Errorf("salt %x, sLen %d: other digest: got %v, want rsa.ErrVerification", salt, sLen, err)
+			}
+		}
+		if err := rsa.VerifyPSS(pub, crypto.SHA256, zero, sig, &rsa.PSSOptions{SaltLength: len(salt)}); err != nil {
+			t.Errorf("salt %x: crypto/rsa rejects the signature: %v", salt, err)
+		}
+		if len(salt) > 0 {
+			if err := VerifyPSS(pub, crypto.SHA256, zero, sig, len(salt)-1); err != rsa.ErrVerification {
+				t.Errorf("salt %x: shorter salt length: got %v, want rsa.ErrVerification", salt, err)
+			}
+		}
+	}
+}
+
+// TestVerifyPSSNonMalleable changes every byte of the encoded message of a
+// valid signature in turn, signs the result with the raw private key
+// operation and checks that the signature no longer verifies. Each region of
+// the encoding is covered: maskedDB, including the bits above emBits, H and
+// the trailer. Changing the signature itself is covered too.
+func TestVerifyPSSNonMalleable(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	hashed := sha256.Sum256([]byte("malleability"))
+	salt := []byte("salt for malleability test")
+	sig, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	em, err := ComputeExpectedEM(pub, crypto.SHA256, hashed[:], salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	emLen := len(em)
+	region := func(i int) string {
+		switch {
+		case i >= emLen-1:
+			return "trailer"
+		case i >= emLen-1-sha256.Size:
+			return "H"
+		default:
+			return "maskedDB"
+		}
+	}
+	for i := range em {
+		for _, bit := range []byte{0x01, 0x80} {
+			mutated := append([]byte(nil), em...)
+			mutated[i] ^= bit
+			s, err := RawDecrypt(rand.Reader, priv, new(big.Int).SetBytes(mutated))
+			if err != nil {
+				// The mutated message is not below the modulus.
+				continue
+			}
+			forged := s.FillBytes(make([]byte, len(sig)))
+			if err := VerifyPSS(pub, crypto.SHA256, hashed[:], forged, len(salt)); !errors.Is(err, rsa.ErrVerification) {
+				t.Errorf("byte %d (%s) ^ %#02x: got %v, want rsa.ErrVerification", i, region(i), bit, err)
+			}
+		}
+	}
+	for i := range sig {
+		mutated := append([]byte(nil), sig...)
+		mutated[i] ^= 0x01
+		if err := VerifyPSS(pub, crypto.SHA256, hashed[:], mutated, len(salt)); !errors.Is(err, rsa.ErrVerification) {
+			t.Errorf("signature byte %d: got %v, want rsa.ErrVerification", i, err)
+		}
+	}
+}
+
+// TestSignPSSEmptyMessage signs the digest of a zero-length message, which
+// unlike an all-zero digest is what hashing nothing really produces.
+func TestSignPSSEmptyMessage(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	if got := sha256.Sum256(nil); !bytes.Equal(got[:], mustHex(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")) {
+		t.Fatalf("SHA-256 of the empty message is %x", got)
+	}
+	for _, hash := range []crypto.Hash{crypto.SHA1, crypto.SHA256, crypto.SHA512} {
+		hashed := hash.New().Sum(nil)
+		for _, saltLen := range []int{0, hash.Size(), MaxSaltLength(pub, hash)} {
+			sig, err := SignPSSAutoSalt(rand.Reader, priv, hash, hashed, saltLen)
+			if err != nil {
+				t.Fatalf("%v, salt length %d: %v", hash, saltLen, err)
+			}
+			for _, sLen := range []int{saltLen, SaltLengthAuto} {
+				if err := VerifyPSS(pub, hash, hashed, sig, sLen); err != nil {
+					t.Errorf("%v, salt length %d, sLen %d: %v", hash, saltLen, sLen, err)
+				}
+			}
+			if err := VerifyPSSStream(pub, hash, strings.NewReader(""), sig, saltLen); err != nil {
+				t.Errorf("%v, salt length %d: streaming the empty message: %v", hash, saltLen, err)
+			}
+			if err := rsa.VerifyPSS(pub, hash, hashed, sig, &rsa.PSSOptions{SaltLength: saltLen}); err != nil {
+				t.Errorf("%v, salt length %d: crypto/rsa rejects the signature: %v", hash, saltLen, err)
+			}
+		}
+	}
+}
+
+// keyWithExponent returns a 1024 bit key whose public exponent is the first
+// valid one from e on, and that exponent.
+func keyWithExponent(t *testing.T, e *big.Int) (*rsa.PrivateKey, *big.Int) {
+	p, err := rand.Prime(rand.Reader, 512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := rand.Prime(rand.Reader, 512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := new(big.Int).Mul(p, q)
+	phi := new(big.Int).Mul(new(big.Int).Sub(p, bigOne), new(big.Int).Sub(q, bigOne))
+	e = new(big.Int).Set(e)
+	d := new(big.Int)
+	for d.ModInverse(e, phi) == nil {
+		e.Add(e, big.NewInt(2))
+	}
+	priv := &rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{N: n},
+		D:         d,
+		Primes:    []*big.Int{p, q},
+	}
+	if e.IsInt64() && int64(int(e.Int64())) == e.Int64() {
+		priv.E = int(e.Int64())
+	}
+	return priv, e
+}
+
+func TestVerifyPSSLargeExponent(t *testing.T) {
+	hashed := sha256.Sum256([]byte("large exponent"))
+	salt := []byte("salt")
+
+	// An exponent that needs more than 64 bits can only be given as a
+	// big.Int.
+	huge := new(big.Int).Lsh(bigOne, 100)
+	huge.Add(huge, bigOne)
+	priv, e := keyWithExponent(t, huge)
+	sig, err := SignPSSFixed(priv, crypto.SHA256, hashed[:], salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyPSSBigExponent(priv.N, e, crypto.SHA256, hashed[:], sig, len(salt)); err != nil {
+		t.Errorf("%d bit exponent: %v", e.BitLen(), err)
+	}
+	if _, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], salt); err == nil {
+		t.Errorf("blinding with an exponent that does not fit in E succeeded")
+	}
+
+	// An exponent that needs 31 bits fits in int on every platform, and
+	// works with rsa.PublicKey.
+	priv, e = keyWithExponent(t, big.NewInt(1<<30+1))
+	sig, err = SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyPSS(&priv.PublicKey, crypto.SHA256, hashed[:], sig, len(salt)); err != nil {
+		t.Errorf("%d bit exponent: %v", e.BitLen(), err)
+	}
+	if err := VerifyPSSBigExponent(priv.N, e, crypto.SHA256, hashed[:], sig, len(salt)); err != nil {
+		t.Errorf("%d bit exponent as a big.Int: %v", e.BitLen(), err)
+	}
+
+	for _, bad := range []int64{0, -3} {
+		if err := VerifyPSSBigExponent(priv.N, big.NewInt(bad), crypto.SHA256, hashed[:], sig, len(salt)); err == nil {
+			t.Errorf("exponent %d accepted", bad)
+		}
+		if err := VerifyPSS(&rsa.PublicKey{N: priv.N, E: int(bad)}, crypto.SHA256, hashed[:], sig, len(salt)); err == nil {
+			t.Errorf("exponent %d in rsa.PublicKey accepted", bad)
+		}
+	}
+}
+
+func BenchmarkSignPSS(b *testing.B) {
+	priv := testKey(b)
+	hashed := sha256.Sum256([]byte("benchmark"))
+	salt := make([]byte, sha256.Size)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], salt); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestVerifyPSSBool(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("bool"))
+	sig, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], []byte("salt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyPSSBool(&priv.PublicKey, crypto.SHA256, hashed[:], sig, 4) {
+		t.Errorf("valid signature rejected")
+	}
+	if VerifyPSSBool(&priv.PublicKey, crypto.SHA256, hashed[:], sig, 5) {
+		t.Errorf("signature with the wrong salt length accepted")
+	}
+	sig[len(sig)-1] ^= 1
+	if VerifyPSSBool(&priv.PublicKey, crypto.SHA256, hashed[:], sig, 4) {
+		t.Errorf("corrupted signature accepted")
+	}
+}
+
+func TestVerifyPSSStrict(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	msg := []byte("high value")
+	hashed := sha256.Sum256(msg)
+	sig, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], []byte("salt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyPSSStrict(pub, crypto.SHA256, msg, hashed[:], sig, 4); err != nil {
+		t.Errorf("valid signature: %v", err)
+	}
+	if err := VerifyPSSStrict(pub, crypto.SHA256, []byte("other"), hashed[:], sig, 4); err != ErrDigestMismatch {
+		t.Errorf("other message: got %v, want ErrDigestMismatch", err)
+	}
+	sha384 := sha512.Sum384(msg)
+	if err := VerifyPSSStrict(pub, crypto.SHA256, msg, sha384[:32], sig, 4); err != ErrDigestMismatch {
+		t.Errorf("digest of another hash: got %v, want ErrDigestMismatch", err)
+	}
+	sig[0] ^= 0x80
+	if err := VerifyPSSStrict(pub, crypto.SHA256, msg, hashed[:], sig, 4); err != rsa.ErrVerification {
+		t.Errorf("corrupted signature: got %v, want rsa.ErrVerification", err)
+	}
+}
+
+func TestVerifyPSSMaxModulusBits(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	hashed := sha256.Sum256([]byte("modulus limit"))
+	sig, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyPSS(pub, crypto.SHA256, hashed[:], sig, 0, WithMaxModulusBits(2048)); err != nil {
+		t.Errorf("key at the limit: %v", err)
+	}
+	if err := VerifyPSS(pub, crypto.SHA256, hashed[:], sig, 0, WithMaxModulusBits(2047)); !errors.Is(err, ErrModulusTooLarge) {
+		t.Errorf("key over the limit: got %v, want ErrModulusTooLarge", err)
+	}
+
+	// A key over the default limit is rejected before the exponentiation,
+	// and before VerifyPSSReader allocates a buffer the size of the modulus.
+	huge := &rsa.PublicKey{N: new(big.Int).Lsh(bigOne, DefaultMaxModulusBits), E: 65537}
+	huge.N.Add(huge.N, bigOne)
+	hugeSig := make([]byte, (huge.N.BitLen()+7)/8)
+	if err := VerifyPSS(huge, crypto.SHA256, hashed[:], hugeSig, 0); !errors.Is(err, ErrModulusTooLarge) {
+		t.Errorf("huge key: got %v, want ErrModulusTooLarge", err)
+	}
+	if err := VerifyPSSReader(huge, crypto.SHA256, hashed[:], bytes.NewReader(hugeSig), 0); !errors.Is(err, ErrModulusTooLarge) {
+		t.Errorf("huge key, reading the signature: got %v, want ErrModulusTooLarge", err)
+	}
+}
+
+func TestDecodeSignature(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	hashed := sha256.Sum256([]byte("two phases"))
+	sig, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], []byte("salt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	emBits := pub.N.BitLen() - 1
+	em, err := DecodeSignature(pub, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := ComputeExpectedEM(pub, crypto.SHA256, hashed[:], []byte("salt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(em, want) {
+		t.Errorf("decoded %x, want %x", em, want)
+	}
+	if err := VerifyDecodedPSS(append([]byte(nil), em...), crypto.SHA256, hashed[:], emBits, 4); err != nil {
+		t.Errorf("valid signature: %v", err)
+	}
+	if err := VerifyDecodedPSS(append([]byte(nil), em...), crypto.SHA256, hashed[:], emBits, 5); err != rsa.ErrVerification {
+		t.Errorf("wrong salt length: got %v, want rsa.ErrVerification", err)
+	}
+	if _, err := DecodeSignature(pub, sig[1:]); err != rsa.ErrVerification {
+		t.Errorf("short signature: got %v, want rsa.ErrVerification", err)
+	}
+}
+
+func TestWarnOnLikelyRawMessage(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	var buf bytes.Buffer
+	warn := []Option{WithWarnOnLikelyRawMessage(), WithLogger(log.New(&buf, "", 0))}
+
+	message := []byte("this message is exactly 32 bytes")
+	sig, err := SignPSS(rand.Reader, priv, crypto.SHA256, message, nil, warn...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "warning") {
+		t.Errorf("signing a message: no warning logged")
+	}
+	buf.Reset()
+	VerifyPSS(pub, crypto.SHA256, message, sig, 0, warn...)
+	if !strings.Contains(buf.String(), "warning") {
+		t.Errorf("verifying a message: no warning logged")
+	}
+
+	buf.Reset()
+	hashed := sha256.Sum256(message)
+	sig, err = SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], nil, warn...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	VerifyPSS(pub, crypto.SHA256, hashed[:], sig, 0, warn...)
+	if buf.Len() != 0 {
+		t.Errorf("digest: logged %q", buf.String())
+	}
+}
+
+func TestSignPSSWithoutBlinding(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("unblinded"))
+	salt := []byte("salt")
+	if _, err := SignPSS(nil, priv, crypto.SHA256, hashed[:], salt); err == nil {
+		t.Errorf("signing with a nil random source succeeded")
+	}
+	want, err := SignPSSFixed(priv, crypto.SHA256, hashed[:], salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, random := range []io.Reader{nil, errReader{}} {
+		sig, err := SignPSS(random, priv, crypto.SHA256, hashed[:], salt, WithoutBlinding())
+		if err != nil {
+			t.Fatalf("random source %T: %v", random, err)
+		}
+		if !bytes.Equal(sig, want) {
+			t.Errorf("random source %T: signature differs from SignPSSFixed", random)
+		}
+	}
+}
+
+// TestVerifyDecodedPSSMismatchedEM passes encoded messages whose length does
+// not match emBits, which must fail without a panic.
+func TestVerifyDecodedPSSMismatchedEM(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	hashed := sha256.Sum256([]byte("mismatched em"))
+	sig, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	em, err := DecodeSignature(pub, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	emBits := pub.N.BitLen() - 1
+	for _, test := range []struct {
+		name   string
+		em     []byte
+		emBits int
+	}{
+		{"em one byte short", em[1:], emBits},
+		{"em one byte long", append([]byte{0}, em...), emBits},
+		{"emBits one byte short", em, emBits - 8},
+		{"emBits one byte long", em, emBits + 8},
+		{"em shorter than the hash", em[:sha256.Size], emBits},
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("%s: panic: %v", test.name, r)
+				}
+			}()
+			err := VerifyDecodedPSS(append([]byte(nil), test.em...), crypto.SHA256, hashed[:], test.emBits, 0)
+			if err != rsa.ErrVerification {
+				t.Errorf("%s: got %v, want rsa.ErrVerification", test.name, err)
+			}
+		}()
+	}
+}
+
+func TestVerifyPSSMinModulusBits(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	hashed := sha256.Sum256([]byte("modulus minimum"))
+	sig, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyPSS(pub, crypto.SHA256, hashed[:], sig, 0, WithMinModulusBits(2048)); err != nil {
+		t.Errorf("key at the minimum: %v", err)
+	}
+	err = VerifyPSS(pub, crypto.SHA256, hashed[:], sig, 0, WithMinModulusBits(3072))
+	if !errors.Is(err, ErrModulusTooSmall) || errors.Is(err, rsa.ErrVerification) {
+		t.Errorf("key below the minimum: got %v, want ErrModulusTooSmall only", err)
+	}
+	if err := ValidatePublicKey(pub, WithMinModulusBits(3072)); !errors.Is(err, ErrModulusTooSmall) {
+		t.Errorf("ValidatePublicKey below the minimum: got %v, want ErrModulusTooSmall", err)
+	}
+}
+
+// TestSignPSSKeyWithoutPrimes signs with a key that has only N, E and D, as
+// imported from a minimal format.
+func TestSignPSSKeyWithoutPrimes(t *testing.T) {
+	full := testKey(t)
+	hashed := sha256.Sum256([]byte("no primes"))
+	salt := []byte("salt")
+	want, err := SignPSSFixed(full, crypto.SHA256, hashed[:], salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	minimal := &rsa.PrivateKey{
+		PublicKey: *PublicKeyFromBytes(full.N.Bytes(), full.E),
+		D:         new(big.Int).Set(full.D),
+	}
+	sig, err := SignPSS(rand.Reader, minimal, crypto.SHA256, hashed[:], salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sig, want) {
+		t.Errorf("signature differs from the one made with the primes")
+	}
+	if err := VerifyPSS(&full.PublicKey, crypto.SHA256, hashed[:], sig, len(salt)); err != nil {
+		t.Error(err)
+	}
+
+	minimal.E = 0
+	if _, err := SignPSS(rand.Reader, minimal, crypto.SHA256, hashed[:], salt); err == nil {
+		t.Errorf("blinded signing without the public exponent succeeded")
+	}
+	sig, err = SignPSS(nil, minimal, crypto.SHA256, hashed[:], salt, WithoutBlinding())
+	if err != nil || !bytes.Equal(sig, want) {
+		t.Errorf("unblinded signing with only N and D: %v", err)
+	}
+}
+
+func TestWithUnsafeIdentityHash(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	msg := []byte("legacy 20 byte token")
+	salt := []byte("legacy salt")
+
+	// Encode the message as the legacy signer does, with the message in
+	// place of mHash: M' = 0^8 || msg || salt.
+	emBits := pub.N.BitLen() - 1
+	emLen := (emBits + 7) / 8
+	h := sha256.New()
+	h.Write(make([]byte, 8))
+	h.Write(msg)
+	h.Write(salt)
+	hPrime := h.Sum(nil)
+	db := make([]byte, emLen-sha256.Size-1)
+	db[len(db)-len(salt)-1] = 0x01
+	copy(db[len(db)-len(salt):], salt)
+	mask := make([]byte, len(db))
+	io.ReadFull(NewMGF1Reader(sha256.New(), hPrime), mask)
+	for i := range db {
+		db[i] ^= mask[i]
+	}
+	db[0] &= 0xFF >> uint(8*emLen-emBits)
+	em := append(append(db, hPrime...), 0xBC)
+	sig := new(big.Int).Exp(new(big.Int).SetBytes(em), priv.D, priv.N).FillBytes(make([]byte, (pub.N.BitLen()+7)/8))
+
+	identity := WithUnsafeIdentityHash(len(msg))
+	for _, sLen := range []int{len(salt), SaltLengthAuto} {
+		if err := VerifyPSS(pub, crypto.SHA256, msg, sig, sLen, identity); err != nil {
+			t.Errorf("sLen %d: %v", sLen, err)
+		}
+	}
+	if err := VerifyPSS(pub, crypto.SHA256, msg, sig, len(salt)); err != rsa.ErrVerification {
+		t.Errorf("without the option: got %v, want rsa.ErrVerification", err)
+	}
+	if err := VerifyPSS(pub, crypto.SHA256, msg, sig, len(salt), WithUnsafeIdentityHash(len(msg)+1)); err != rsa.ErrVerification {
+		t.Errorf("wrong message length: got %v, want rsa.ErrVerification", err)
+	}
+	hashed := sha256.Sum256(msg)
+	digestSig, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mLen := range []int{0, -1} {
+		if err := VerifyPSS(pub, crypto.SHA256, hashed[:], digestSig, len(salt), WithUnsafeIdentityHash(mLen)); err == nil {
+			t.Errorf("message length %d: signature verified", mLen)
+		}
+	}
+	other := append([]byte(nil), msg...)
+	other[0] ^= 1
+	if err := VerifyPSS(pub, crypto.SHA256, other, sig, len(salt), identity); err != rsa.ErrVerification {
+		t.Errorf("other message: got %v, want rsa.ErrVerification", err)
+	}
+	if _, err := SignPSS(rand.Reader, priv, crypto.SHA256, msg, salt, identity); err == nil {
+		t.Errorf("signing accepted a message that is not a digest")
+	}
+}
@@ -3,7 +3,7 @@ package pss
 import (
 	"crypto"
 	"crypto/rand"
-	. "crypto/rsa"
+	"crypto/rsa"
 	"crypto/sha1"
 	"fmt"
 	"math/big"
@@ -13,7 +13,7 @@ import (
 
 func TestPSSSign_1_1_0(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("a56e4a0e701017589a5187dc7ea841d156f2ec0e36ad52a44dfeb1e61f7ad991d8c51056ffedb162b4c0f283a12a88a394dff526ab7291cbb307ceabfce0b1dfd5cd9508096d5b2b8b6df5d671ef6377c0921cb23c270a70e2598e6ff89d19f105acc2d3f0cb35f29280e1386b6f64c4ef22e1e1f20d0ce8cffb2249bd9a2137"),
 			E: 0x010001,
 		},
@@ -37,7 +37,7 @@ func TestPSSSign_1_1_0(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -51,7 +51,7 @@ func TestPSSSign_1_1_0(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -86,7 +86,7 @@ func TestPSSSign_1_1_0(t *testing.T) {
 
 func TestPSSSign_1_2_1(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("a56e4a0e701017589a5187dc7ea841d156f2ec0e36ad52a44dfeb1e61f7ad991d8c51056ffedb162b4c0f283a12a88a394dff526ab7291cbb307ceabfce0b1dfd5cd9508096d5b2b8b6df5d671ef6377c0921cb23c270a70e2598e6ff89d19f105acc2d3f0cb35f29280e1386b6f64c4ef22e1e1f20d0ce8cffb2249bd9a2137"),
 			E: 0x010001,
 		},
@@ -110,7 +110,7 @@ func TestPSSSign_1_2_1(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -124,7 +124,7 @@ func TestPSSSign_1_2_1(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -159,7 +159,7 @@ func TestPSSSign_1_2_1(t *testing.T) {
 
 func TestPSSSign_1_3_2(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("a56e4a0e701017589a5187dc7ea841d156f2ec0e36ad52a44dfeb1e61f7ad991d8c51056ffedb162b4c0f283a12a88a394dff526ab7291cbb307ceabfce0b1dfd5cd9508096d5b2b8b6df5d671ef6377c0921cb23c270a70e2598e6ff89d19f105acc2d3f0cb35f29280e1386b6f64c4ef22e1e1f20d0ce8cffb2249bd9a2137"),
 			E: 0x010001,
 		},
@@ -183,7 +183,7 @@ func TestPSSSign_1_3_2(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -197,7 +197,7 @@ func TestPSSSign_1_3_2(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -232,7 +232,7 @@ func TestPSSSign_1_3_2(t *testing.T) {
 
 func TestPSSSign_1_4_3(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("a56e4a0e701017589a5187dc7ea841d156f2ec0e36ad52a44dfeb1e61f7ad991d8c51056ffedb162b4c0f283a12a88a394dff526ab7291cbb307ceabfce0b1dfd5cd9508096d5b2b8b6df5d671ef6377c0921cb23c270a70e2598e6ff89d19f105acc2d3f0cb35f29280e1386b6f64c4ef22e1e1f20d0ce8cffb2249bd9a2137"),
 			E: 0x010001,
 		},
@@ -256,7 +256,7 @@ func TestPSSSign_1_4_3(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -270,7 +270,7 @@ func TestPSSSign_1_4_3(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -305,7 +305,7 @@ func TestPSSSign_1_4_3(t *testing.T) {
 
 func TestPSSSign_1_5_4(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("a56e4a0e701017589a5187dc7ea841d156f2ec0e36ad52a44dfeb1e61f7ad991d8c51056ffedb162b4c0f283a12a88a394dff526ab7291cbb307ceabfce0b1dfd5cd9508096d5b2b8b6df5d671ef6377c0921cb23c270a70e2598e6ff89d19f105acc2d3f0cb35f29280e1386b6f64c4ef22e1e1f20d0ce8cffb2249bd9a2137"),
 			E: 0x010001,
 		},
@@ -329,7 +329,7 @@ func TestPSSSign_1_5_4(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -343,7 +343,7 @@ func TestPSSSign_1_5_4(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -378,7 +378,7 @@ func TestPSSSign_1_5_4(t *testing.T) {
 
 func TestPSSSign_2_1_5(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("01d40c1bcf97a68ae7cdbd8a7bf3e34fa19dcca4ef75a47454375f94514d88fed006fb829f8419ff87d6315da68a1ff3a0938e9abb3464011c303ad99199cf0c7c7a8b477dce829e8844f625b115e5e9c4a59cf8f8113b6834336a2fd2689b472cbb5e5cabe674350c59b6c17e176874fb42f8fc3d176a017edc61fd326c4b33c9"),
 			E: 0x010001,
 		},
@@ -402,7 +402,7 @@ func TestPSSSign_2_1_5(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -416,7 +416,7 @@ func TestPSSSign_2_1_5(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -451,7 +451,7 @@ func TestPSSSign_2_1_5(t *testing.T) {
 
 func TestPSSSign_2_2_6(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("01d40c1bcf97a68ae7cdbd8a7bf3e34fa19dcca4ef75a47454375f94514d88fed006fb829f8419ff87d6315da68a1ff3a0938e9abb3464011c303ad99199cf0c7c7a8b477dce829e8844f625b115e5e9c4a59cf8f8113b6834336a2fd2689b472cbb5e5cabe674350c59b6c17e176874fb42f8fc3d176a017edc61fd326c4b33c9"),
 			E: 0x010001,
 		},
@@ -475,7 +475,7 @@ func TestPSSSign_2_2_6(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -489,7 +489,7 @@ func TestPSSSign_2_2_6(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -524,7 +524,7 @@ func TestPSSSign_2_2_6(t *testing.T) {
 
 func TestPSSSign_2_3_7(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("01d40c1bcf97a68ae7cdbd8a7bf3e34fa19dcca4ef75a47454375f94514d88fed006fb829f8419ff87d6315da68a1ff3a0938e9abb3464011c303ad99199cf0c7c7a8b477dce829e8844f625b115e5e9c4a59cf8f8113b6834336a2fd2689b472cbb5e5cabe674350c59b6c17e176874fb42f8fc3d176a017edc61fd326c4b33c9"),
 			E: 0x010001,
 		},
@@ -548,7 +548,7 @@ func TestPSSSign_2_3_7(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -562,7 +562,7 @@ func TestPSSSign_2_3_7(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -597,7 +597,7 @@ func TestPSSSign_2_3_7(t *testing.T) {
 
 func TestPSSSign_2_4_8(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("01d40c1bcf97a68ae7cdbd8a7bf3e34fa19dcca4ef75a47454375f94514d88fed006fb829f8419ff87d6315da68a1ff3a0938e9abb3464011c303ad99199cf0c7c7a8b477dce829e8844f625b115e5e9c4a59cf8f8113b6834336a2fd2689b472cbb5e5cabe674350c59b6c17e176874fb42f8fc3d176a017edc61fd326c4b33c9"),
 			E: 0x010001,
 		},
@@ -621,7 +621,7 @@ func TestPSSSign_2_4_8(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -635,7 +635,7 @@ func TestPSSSign_2_4_8(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -670,7 +670,7 @@ func TestPSSSign_2_4_8(t *testing.T) {
 
 func TestPSSSign_2_5_9(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("01d40c1bcf97a68ae7cdbd8a7bf3e34fa19dcca4ef75a47454375f94514d88fed006fb829f8419ff87d6315da68a1ff3a0938e9abb3464011c303ad99199cf0c7c7a8b477dce829e8844f625b115e5e9c4a59cf8f8113b6834336a2fd2689b472cbb5e5cabe674350c59b6c17e176874fb42f8fc3d176a017edc61fd326c4b33c9"),
 			E: 0x010001,
 		},
@@ -694,7 +694,7 @@ func TestPSSSign_2_5_9(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -708,7 +708,7 @@ func TestPSSSign_2_5_9(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -743,7 +743,7 @@ func TestPSSSign_2_5_9(t *testing.T) {
 
 func TestPSSSign_3_1_10(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("02f246ef451ed3eebb9a310200cc25859c048e4be798302991112eb68ce6db674e280da21feded1ae74880ca522b18db249385012827c515f0e466a1ffa691d98170574e9d0eadb087586ca48933da3cc953d95bd0ed50de10ddcb6736107d6c831c7f663e833ca4c097e700ce0fb945f88fb85fe8e5a773172565b914a471a443"),
 			E: 0x010001,
 		},
@@ -767,7 +767,7 @@ func TestPSSSign_3_1_10(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -781,7 +781,7 @@ func TestPSSSign_3_1_10(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -816,7 +816,7 @@ func TestPSSSign_3_1_10(t *testing.T) {
 
 func TestPSSSign_3_2_11(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("02f246ef451ed3eebb9a310200cc25859c048e4be798302991112eb68ce6db674e280da21feded1ae74880ca522b18db249385012827c515f0e466a1ffa691d98170574e9d0eadb087586ca48933da3cc953d95bd0ed50de10ddcb6736107d6c831c7f663e833ca4c097e700ce0fb945f88fb85fe8e5a773172565b914a471a443"),
 			E: 0x010001,
 		},
@@ -840,7 +840,7 @@ func TestPSSSign_3_2_11(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -854,7 +854,7 @@ func TestPSSSign_3_2_11(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -889,7 +889,7 @@ func TestPSSSign_3_2_11(t *testing.T) {
 
 func TestPSSSign_3_3_12(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("02f246ef451ed3eebb9a310200cc25859c048e4be798302991112eb68ce6db674e280da21feded1ae74880ca522b18db249385012827c515f0e466a1ffa691d98170574e9d0eadb087586ca48933da3cc953d95bd0ed50de10ddcb6736107d6c831c7f663e833ca4c097e700ce0fb945f88fb85fe8e5a773172565b914a471a443"),
 			E: 0x010001,
 		},
@@ -913,7 +913,7 @@ func TestPSSSign_3_3_12(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -927,7 +927,7 @@ func TestPSSSign_3_3_12(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -962,7 +962,7 @@ func TestPSSSign_3_3_12(t *testing.T) {
 
 func TestPSSSign_3_4_13(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("02f246ef451ed3eebb9a310200cc25859c048e4be798302991112eb68ce6db674e280da21feded1ae74880ca522b18db249385012827c515f0e466a1ffa691d98170574e9d0eadb087586ca48933da3cc953d95bd0ed50de10ddcb6736107d6c831c7f663e833ca4c097e700ce0fb945f88fb85fe8e5a773172565b914a471a443"),
 			E: 0x010001,
 		},
@@ -986,7 +986,7 @@ func TestPSSSign_3_4_13(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -1000,7 +1000,7 @@ func TestPSSSign_3_4_13(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -1035,7 +1035,7 @@ func TestPSSSign_3_4_13(t *testing.T) {
 
 func TestPSSSign_3_5_14(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("02f246ef451ed3eebb9a310200cc25859c048e4be798302991112eb68ce6db674e280da21feded1ae74880ca522b18db249385012827c515f0e466a1ffa691d98170574e9d0eadb087586ca48933da3cc953d95bd0ed50de10ddcb6736107d6c831c7f663e833ca4c097e700ce0fb945f88fb85fe8e5a773172565b914a471a443"),
 			E: 0x010001,
 		},
@@ -1059,7 +1059,7 @@ func TestPSSSign_3_5_14(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -1073,7 +1073,7 @@ func TestPSSSign_3_5_14(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -1108,7 +1108,7 @@ func TestPSSSign_3_5_14(t *testing.T) {
 
 func TestPSSSign_4_1_15(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("054adb7886447efe6f57e0368f06cf52b0a3370760d161cef126b91be7f89c421b62a6ec1da3c311d75ed50e0ab5fff3fd338acc3aa8a4e77ee26369acb81ba900fa83f5300cf9bb6c53ad1dc8a178b815db4235a9a9da0c06de4e615ea1277ce559e9c108de58c14a81aa77f5a6f8d1335494498848c8b95940740be7bf7c3705"),
 			E: 0x010001,
 		},
@@ -1132,7 +1132,7 @@ func TestPSSSign_4_1_15(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -1146,7 +1146,7 @@ func TestPSSSign_4_1_15(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -1181,7 +1181,7 @@ func TestPSSSign_4_1_15(t *testing.T) {
 
 func TestPSSSign_4_2_16(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("054adb7886447efe6f57e0368f06cf52b0a3370760d161cef126b91be7f89c421b62a6ec1da3c311d75ed50e0ab5fff3fd338acc3aa8a4e77ee26369acb81ba900fa83f5300cf9bb6c53ad1dc8a178b815db4235a9a9da0c06de4e615ea1277ce559e9c108de58c14a81aa77f5a6f8d1335494498848c8b95940740be7bf7c3705"),
 			E: 0x010001,
 		},
@@ -1205,7 +1205,7 @@ func TestPSSSign_4_2_16(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -1219,7 +1219,7 @@ func TestPSSSign_4_2_16(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -1254,7 +1254,7 @@ func TestPSSSign_4_2_16(t *testing.T) {
 
 func TestPSSSign_4_3_17(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("054adb7886447efe6f57e0368f06cf52b0a3370760d161cef126b91be7f89c421b62a6ec1da3c311d75ed50e0ab5fff3fd338acc3aa8a4e77ee26369acb81ba900fa83f5300cf9bb6c53ad1dc8a178b815db4235a9a9da0c06de4e615ea1277ce559e9c108de58c14a81aa77f5a6f8d1335494498848c8b95940740be7bf7c3705"),
 			E: 0x010001,
 		},
@@ -1278,7 +1278,7 @@ func TestPSSSign_4_3_17(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -1292,7 +1292,7 @@ func TestPSSSign_4_3_17(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -1327,7 +1327,7 @@ func TestPSSSign_4_3_17(t *testing.T) {
 
 func TestPSSSign_4_4_18(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("054adb7886447efe6f57e0368f06cf52b0a3370760d161cef126b91be7f89c421b62a6ec1da3c311d75ed50e0ab5fff3fd338acc3aa8a4e77ee26369acb81ba900fa83f5300cf9bb6c53ad1dc8a178b815db4235a9a9da0c06de4e615ea1277ce559e9c108de58c14a81aa77f5a6f8d1335494498848c8b95940740be7bf7c3705"),
 			E: 0x010001,
 		},
@@ -1351,7 +1351,7 @@ func TestPSSSign_4_4_18(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -1365,7 +1365,7 @@ func TestPSSSign_4_4_18(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -1400,7 +1400,7 @@ func TestPSSSign_4_4_18(t *testing.T) {
 
 func TestPSSSign_4_5_19(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("054adb7886447efe6f57e0368f06cf52b0a3370760d161cef126b91be7f89c421b62a6ec1da3c311d75ed50e0ab5fff3fd338acc3aa8a4e77ee26369acb81ba900fa83f5300cf9bb6c53ad1dc8a178b815db4235a9a9da0c06de4e615ea1277ce559e9c108de58c14a81aa77f5a6f8d1335494498848c8b95940740be7bf7c3705"),
 			E: 0x010001,
 		},
@@ -1424,7 +1424,7 @@ func TestPSSSign_4_5_19(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -1438,7 +1438,7 @@ func TestPSSSign_4_5_19(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -1473,7 +1473,7 @@ func TestPSSSign_4_5_19(t *testing.T) {
 
 func TestPSSSign_5_1_20(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("0d10f661f29940f5ed39aa260966deb47843679d2b6fb25b3de370f3ac7c19916391fd25fb527ebfa6a4b4df45a1759d996c4bb4ebd18828c44fc52d0191871740525f47a4b0cc8da325ed8aa676b0d0f626e0a77f07692170acac8082f42faa7dc7cd123e730e31a87985204cabcbe6670d43a2dd2b2ddef5e05392fc213bc507"),
 			E: 0x010001,
 		},
@@ -1497,7 +1497,7 @@ func TestPSSSign_5_1_20(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -1511,7 +1511,7 @@ func TestPSSSign_5_1_20(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -1546,7 +1546,7 @@ func TestPSSSign_5_1_20(t *testing.T) {
 
 func TestPSSSign_5_2_21(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("0d10f661f29940f5ed39aa260966deb47843679d2b6fb25b3de370f3ac7c19916391fd25fb527ebfa6a4b4df45a1759d996c4bb4ebd18828c44fc52d0191871740525f47a4b0cc8da325ed8aa676b0d0f626e0a77f07692170acac8082f42faa7dc7cd123e730e31a87985204cabcbe6670d43a2dd2b2ddef5e05392fc213bc507"),
 			E: 0x010001,
 		},
@@ -1570,7 +1570,7 @@ func TestPSSSign_5_2_21(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -1584,7 +1584,7 @@ func TestPSSSign_5_2_21(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -1619,7 +1619,7 @@ func TestPSSSign_5_2_21(t *testing.T) {
 
 func TestPSSSign_5_3_22(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("0d10f661f29940f5ed39aa260966deb47843679d2b6fb25b3de370f3ac7c19916391fd25fb527ebfa6a4b4df45a1759d996c4bb4ebd18828c44fc52d0191871740525f47a4b0cc8da325ed8aa676b0d0f626e0a77f07692170acac8082f42faa7dc7cd123e730e31a87985204cabcbe6670d43a2dd2b2ddef5e05392fc213bc507"),
 			E: 0x010001,
 		},
@@ -1643,7 +1643,7 @@ func TestPSSSign_5_3_22(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -1657,7 +1657,7 @@ func TestPSSSign_5_3_22(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -1692,7 +1692,7 @@ func TestPSSSign_5_3_22(t *testing.T) {
 
 func TestPSSSign_5_4_23(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("0d10f661f29940f5ed39aa260966deb47843679d2b6fb25b3de370f3ac7c19916391fd25fb527ebfa6a4b4df45a1759d996c4bb4ebd18828c44fc52d0191871740525f47a4b0cc8da325ed8aa676b0d0f626e0a77f07692170acac8082f42faa7dc7cd123e730e31a87985204cabcbe6670d43a2dd2b2ddef5e05392fc213bc507"),
 			E: 0x010001,
 		},
@@ -1716,7 +1716,7 @@ func TestPSSSign_5_4_23(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -1730,7 +1730,7 @@ func TestPSSSign_5_4_23(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -1765,7 +1765,7 @@ func TestPSSSign_5_4_23(t *testing.T) {
 
 func TestPSSSign_5_5_24(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("0d10f661f29940f5ed39aa260966deb47843679d2b6fb25b3de370f3ac7c19916391fd25fb527ebfa6a4b4df45a1759d996c4bb4ebd18828c44fc52d0191871740525f47a4b0cc8da325ed8aa676b0d0f626e0a77f07692170acac8082f42faa7dc7cd123e730e31a87985204cabcbe6670d43a2dd2b2ddef5e05392fc213bc507"),
 			E: 0x010001,
 		},
@@ -1789,7 +1789,7 @@ func TestPSSSign_5_5_24(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -1803,7 +1803,7 @@ func TestPSSSign_5_5_24(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -1838,7 +1838,7 @@ func TestPSSSign_5_5_24(t *testing.T) {
 
 func TestPSSSign_6_1_25(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("164ca31cff609f3a0e7101b039f2e4fe6dd37519ab98598d179e174996598071f47d3a04559158d7be373cf1aa53f0aa6ef09039e5678c2a4c63900514c8c4f8aaed5de12a5f10b09c311af8c0ffb5b7a297f2efc63b8d6b0510931f0b98e48bf5fc6ec4e7b8db1ffaeb08c38e02adb8f03a48229c99e969431f61cb8c4dc698d1"),
 			E: 0x010001,
 		},
@@ -1862,7 +1862,7 @@ func TestPSSSign_6_1_25(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -1876,7 +1876,7 @@ func TestPSSSign_6_1_25(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -1911,7 +1911,7 @@ func TestPSSSign_6_1_25(t *testing.T) {
 
 func TestPSSSign_6_2_26(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("164ca31cff609f3a0e7101b039f2e4fe6dd37519ab98598d179e174996598071f47d3a04559158d7be373cf1aa53f0aa6ef09039e5678c2a4c63900514c8c4f8aaed5de12a5f10b09c311af8c0ffb5b7a297f2efc63b8d6b0510931f0b98e48bf5fc6ec4e7b8db1ffaeb08c38e02adb8f03a48229c99e969431f61cb8c4dc698d1"),
 			E: 0x010001,
 		},
@@ -1935,7 +1935,7 @@ func TestPSSSign_6_2_26(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -1949,7 +1949,7 @@ func TestPSSSign_6_2_26(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -1984,7 +1984,7 @@ func TestPSSSign_6_2_26(t *testing.T) {
 
 func TestPSSSign_6_3_27(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("164ca31cff609f3a0e7101b039f2e4fe6dd37519ab98598d179e174996598071f47d3a04559158d7be373cf1aa53f0aa6ef09039e5678c2a4c63900514c8c4f8aaed5de12a5f10b09c311af8c0ffb5b7a297f2efc63b8d6b0510931f0b98e48bf5fc6ec4e7b8db1ffaeb08c38e02adb8f03a48229c99e969431f61cb8c4dc698d1"),
 			E: 0x010001,
 		},
@@ -2008,7 +2008,7 @@ func TestPSSSign_6_3_27(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -2022,7 +2022,7 @@ func TestPSSSign_6_3_27(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -2057,7 +2057,7 @@ func TestPSSSign_6_3_27(t *testing.T) {
 
 func TestPSSSign_6_4_28(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("164ca31cff609f3a0e7101b039f2e4fe6dd37519ab98598d179e174996598071f47d3a04559158d7be373cf1aa53f0aa6ef09039e5678c2a4c63900514c8c4f8aaed5de12a5f10b09c311af8c0ffb5b7a297f2efc63b8d6b0510931f0b98e48bf5fc6ec4e7b8db1ffaeb08c38e02adb8f03a48229c99e969431f61cb8c4dc698d1"),
 			E: 0x010001,
 		},
@@ -2081,7 +2081,7 @@ func TestPSSSign_6_4_28(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -2095,7 +2095,7 @@ func TestPSSSign_6_4_28(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -2130,7 +2130,7 @@ func TestPSSSign_6_4_28(t *testing.T) {
 
 func TestPSSSign_6_5_29(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("164ca31cff609f3a0e7101b039f2e4fe6dd37519ab98598d179e174996598071f47d3a04559158d7be373cf1aa53f0aa6ef09039e5678c2a4c63900514c8c4f8aaed5de12a5f10b09c311af8c0ffb5b7a297f2efc63b8d6b0510931f0b98e48bf5fc6ec4e7b8db1ffaeb08c38e02adb8f03a48229c99e969431f61cb8c4dc698d1"),
 			E: 0x010001,
 		},
@@ -2154,7 +2154,7 @@ func TestPSSSign_6_5_29(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -2168,7 +2168,7 @@ func TestPSSSign_6_5_29(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -2203,7 +2203,7 @@ func TestPSSSign_6_5_29(t *testing.T) {
 
 func TestPSSSign_7_1_30(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("37c9da4a66c8c408b8da27d0c9d79f8ccb1eafc1d2fe48746d940b7c4ef5dee18ad12647cefaa0c4b3188b221c515386759b93f02024b25ab9242f8357d8f3fd49640ee5e643eaf6c64deefa7089727c8ff03993333915c6ef21bf5975b6e50d118b51008ec33e9f01a0a545a10a836a43ddbca9d8b5c5d3548022d7064ea29ab3"),
 			E: 0x010001,
 		},
@@ -2227,7 +2227,7 @@ func TestPSSSign_7_1_30(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -2241,7 +2241,7 @@ func TestPSSSign_7_1_30(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -2276,7 +2276,7 @@ func TestPSSSign_7_1_30(t *testing.T) {
 
 func TestPSSSign_7_2_31(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("37c9da4a66c8c408b8da27d0c9d79f8ccb1eafc1d2fe48746d940b7c4ef5dee18ad12647cefaa0c4b3188b221c515386759b93f02024b25ab9242f8357d8f3fd49640ee5e643eaf6c64deefa7089727c8ff03993333915c6ef21bf5975b6e50d118b51008ec33e9f01a0a545a10a836a43ddbca9d8b5c5d3548022d7064ea29ab3"),
 			E: 0x010001,
 		},
@@ -2300,7 +2300,7 @@ func TestPSSSign_7_2_31(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -2314,7 +2314,7 @@ func TestPSSSign_7_2_31(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -2349,7 +2349,7 @@ func TestPSSSign_7_2_31(t *testing.T) {
 
 func TestPSSSign_7_3_32(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("37c9da4a66c8c408b8da27d0c9d79f8ccb1eafc1d2fe48746d940b7c4ef5dee18ad12647cefaa0c4b3188b221c515386759b93f02024b25ab9242f8357d8f3fd49640ee5e643eaf6c64deefa7089727c8ff03993333915c6ef21bf5975b6e50d118b51008ec33e9f01a0a545a10a836a43ddbca9d8b5c5d3548022d7064ea29ab3"),
 			E: 0x010001,
 		},
@@ -2373,7 +2373,7 @@ func TestPSSSign_7_3_32(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -2387,7 +2387,7 @@ func TestPSSSign_7_3_32(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -2422,7 +2422,7 @@ func TestPSSSign_7_3_32(t *testing.T) {
 
 func TestPSSSign_7_4_33(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("37c9da4a66c8c408b8da27d0c9d79f8ccb1eafc1d2fe48746d940b7c4ef5dee18ad12647cefaa0c4b3188b221c515386759b93f02024b25ab9242f8357d8f3fd49640ee5e643eaf6c64deefa7089727c8ff03993333915c6ef21bf5975b6e50d118b51008ec33e9f01a0a545a10a836a43ddbca9d8b5c5d3548022d7064ea29ab3"),
 			E: 0x010001,
 		},
@@ -2446,7 +2446,7 @@ func TestPSSSign_7_4_33(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -2460,7 +2460,7 @@ func TestPSSSign_7_4_33(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -2495,7 +2495,7 @@ func TestPSSSign_7_4_33(t *testing.T) {
 
 func TestPSSSign_7_5_34(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("37c9da4a66c8c408b8da27d0c9d79f8ccb1eafc1d2fe48746d940b7c4ef5dee18ad12647cefaa0c4b3188b221c515386759b93f02024b25ab9242f8357d8f3fd49640ee5e643eaf6c64deefa7089727c8ff03993333915c6ef21bf5975b6e50d118b51008ec33e9f01a0a545a10a836a43ddbca9d8b5c5d3548022d7064ea29ab3"),
 			E: 0x010001,
 		},
@@ -2519,7 +2519,7 @@ func TestPSSSign_7_5_34(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -2533,7 +2533,7 @@ func TestPSSSign_7_5_34(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -2568,7 +2568,7 @@ func TestPSSSign_7_5_34(t *testing.T) {
 
 func TestPSSSign_8_1_35(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("495370a1fb18543c16d3631e3163255df62be6eee890d5f25509e4f778a8ea6fbbbcdf85dff64e0d972003ab3681fbba6dd41fd541829b2e582de9f2a4a4e0a2d0900bef4753db3cee0ee06c7dfae8b1d53b5953218f9cceea695b08668edeaadced9463b1d790d5ebf27e9115b46cad4d9a2b8efab0561b0810344739ada0733f"),
 			E: 0x010001,
 		},
@@ -2592,7 +2592,7 @@ func TestPSSSign_8_1_35(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -2606,7 +2606,7 @@ func TestPSSSign_8_1_35(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -2641,7 +2641,7 @@ func TestPSSSign_8_1_35(t *testing.T) {
 
 func TestPSSSign_8_2_36(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("495370a1fb18543c16d3631e3163255df62be6eee890d5f25509e4f778a8ea6fbbbcdf85dff64e0d972003ab3681fbba6dd41fd541829b2e582de9f2a4a4e0a2d0900bef4753db3cee0ee06c7dfae8b1d53b5953218f9cceea695b08668edeaadced9463b1d790d5ebf27e9115b46cad4d9a2b8efab0561b0810344739ada0733f"),
 			E: 0x010001,
 		},
@@ -2665,7 +2665,7 @@ func TestPSSSign_8_2_36(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -2679,7 +2679,7 @@ func TestPSSSign_8_2_36(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -2714,7 +2714,7 @@ func TestPSSSign_8_2_36(t *testing.T) {
 
 func TestPSSSign_8_3_37(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("495370a1fb18543c16d3631e3163255df62be6eee890d5f25509e4f778a8ea6fbbbcdf85dff64e0d972003ab3681fbba6dd41fd541829b2e582de9f2a4a4e0a2d0900bef4753db3cee0ee06c7dfae8b1d53b5953218f9cceea695b08668edeaadced9463b1d790d5ebf27e9115b46cad4d9a2b8efab0561b0810344739ada0733f"),
 			E: 0x010001,
 		},
@@ -2738,7 +2738,7 @@ func TestPSSSign_8_3_37(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -2752,7 +2752,7 @@ func TestPSSSign_8_3_37(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -2787,7 +2787,7 @@ func TestPSSSign_8_3_37(t *testing.T) {
 
 func TestPSSSign_8_4_38(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("495370a1fb18543c16d3631e3163255df62be6eee890d5f25509e4f778a8ea6fbbbcdf85dff64e0d972003ab3681fbba6dd41fd541829b2e582de9f2a4a4e0a2d0900bef4753db3cee0ee06c7dfae8b1d53b5953218f9cceea695b08668edeaadced9463b1d790d5ebf27e9115b46cad4d9a2b8efab0561b0810344739ada0733f"),
 			E: 0x010001,
 		},
@@ -2811,7 +2811,7 @@ func TestPSSSign_8_4_38(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -2825,7 +2825,7 @@ func TestPSSSign_8_4_38(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -2860,7 +2860,7 @@ func TestPSSSign_8_4_38(t *testing.T) {
 
 func TestPSSSign_8_5_39(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("495370a1fb18543c16d3631e3163255df62be6eee890d5f25509e4f778a8ea6fbbbcdf85dff64e0d972003ab3681fbba6dd41fd541829b2e582de9f2a4a4e0a2d0900bef4753db3cee0ee06c7dfae8b1d53b5953218f9cceea695b08668edeaadced9463b1d790d5ebf27e9115b46cad4d9a2b8efab0561b0810344739ada0733f"),
 			E: 0x010001,
 		},
@@ -2884,7 +2884,7 @@ func TestPSSSign_8_5_39(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -2898,7 +2898,7 @@ func TestPSSSign_8_5_39(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -2933,7 +2933,7 @@ func TestPSSSign_8_5_39(t *testing.T) {
 
 func TestPSSSign_9_1_40(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("e6bd692ac96645790403fdd0f5beb8b9bf92ed10007fc365046419dd06c05c5b5b2f48ecf989e4ce269109979cbb40b4a0ad24d22483d1ee315ad4ccb1534268352691c524f6dd8e6c29d224cf246973aec86c5bf6b1401a850d1b9ad1bb8cbcec47b06f0f8c7f45d3fc8f319299c5433ddbc2b3053b47ded2ecd4a4caefd614833dc8bb622f317ed076b8057fe8de3f84480ad5e83e4a61904a4f248fb397027357e1d30e463139815c6fd4fd5ac5b8172a45230ecb6318a04f1455d84e5a8b"),
 			E: 0x010001,
 		},
@@ -2957,7 +2957,7 @@ func TestPSSSign_9_1_40(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -2971,7 +2971,7 @@ func TestPSSSign_9_1_40(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -3006,7 +3006,7 @@ func TestPSSSign_9_1_40(t *testing.T) {
 
 func TestPSSSign_9_2_41(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("e6bd692ac96645790403fdd0f5beb8b9bf92ed10007fc365046419dd06c05c5b5b2f48ecf989e4ce269109979cbb40b4a0ad24d22483d1ee315ad4ccb1534268352691c524f6dd8e6c29d224cf246973aec86c5bf6b1401a850d1b9ad1bb8cbcec47b06f0f8c7f45d3fc8f319299c5433ddbc2b3053b47ded2ecd4a4caefd614833dc8bb622f317ed076b8057fe8de3f84480ad5e83e4a61904a4f248fb397027357e1d30e463139815c6fd4fd5ac5b8172a45230ecb6318a04f1455d84e5a8b"),
 			E: 0x010001,
 		},
@@ -3030,7 +3030,7 @@ func TestPSSSign_9_2_41(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -3044,7 +3044,7 @@ func TestPSSSign_9_2_41(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -3079,7 +3079,7 @@ func TestPSSSign_9_2_41(t *testing.T) {
 
 func TestPSSSign_9_3_42(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("e6bd692ac96645790403fdd0f5beb8b9bf92ed10007fc365046419dd06c05c5b5b2f48ecf989e4ce269109979cbb40b4a0ad24d22483d1ee315ad4ccb1534268352691c524f6dd8e6c29d224cf246973aec86c5bf6b1401a850d1b9ad1bb8cbcec47b06f0f8c7f45d3fc8f319299c5433ddbc2b3053b47ded2ecd4a4caefd614833dc8bb622f317ed076b8057fe8de3f84480ad5e83e4a61904a4f248fb397027357e1d30e463139815c6fd4fd5ac5b8172a45230ecb6318a04f1455d84e5a8b"),
 			E: 0x010001,
 		},
@@ -3103,7 +3103,7 @@ func TestPSSSign_9_3_42(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -3117,7 +3117,7 @@ func TestPSSSign_9_3_42(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -3152,7 +3152,7 @@ func TestPSSSign_9_3_42(t *testing.T) {
 
 func TestPSSSign_9_4_43(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("e6bd692ac96645790403fdd0f5beb8b9bf92ed10007fc365046419dd06c05c5b5b2f48ecf989e4ce269109979cbb40b4a0ad24d22483d1ee315ad4ccb1534268352691c524f6dd8e6c29d224cf246973aec86c5bf6b1401a850d1b9ad1bb8cbcec47b06f0f8c7f45d3fc8f319299c5433ddbc2b3053b47ded2ecd4a4caefd614833dc8bb622f317ed076b8057fe8de3f84480ad5e83e4a61904a4f248fb397027357e1d30e463139815c6fd4fd5ac5b8172a45230ecb6318a04f1455d84e5a8b"),
 			E: 0x010001,
 		},
@@ -3176,7 +3176,7 @@ func TestPSSSign_9_4_43(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -3190,7 +3190,7 @@ func TestPSSSign_9_4_43(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -3225,7 +3225,7 @@ func TestPSSSign_9_4_43(t *testing.T) {
 
 func TestPSSSign_9_5_44(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("e6bd692ac96645790403fdd0f5beb8b9bf92ed10007fc365046419dd06c05c5b5b2f48ecf989e4ce269109979cbb40b4a0ad24d22483d1ee315ad4ccb1534268352691c524f6dd8e6c29d224cf246973aec86c5bf6b1401a850d1b9ad1bb8cbcec47b06f0f8c7f45d3fc8f319299c5433ddbc2b3053b47ded2ecd4a4caefd614833dc8bb622f317ed076b8057fe8de3f84480ad5e83e4a61904a4f248fb397027357e1d30e463139815c6fd4fd5ac5b8172a45230ecb6318a04f1455d84e5a8b"),
 			E: 0x010001,
 		},
@@ -3249,7 +3249,7 @@ func TestPSSSign_9_5_44(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -3263,7 +3263,7 @@ func TestPSSSign_9_5_44(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -3298,7 +3298,7 @@ func TestPSSSign_9_5_44(t *testing.T) {
 
 func TestPSSSign_10_1_45(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("a5dd867ac4cb02f90b9457d48c14a770ef991c56c39c0ec65fd11afa8937cea57b9be7ac73b45c0017615b82d622e318753b6027c0fd157be12f8090fee2a7adcd0eef759f88ba4997c7a42d58c9aa12cb99ae001fe521c13bb5431445a8d5ae4f5e4c7e948ac227d3604071f20e577e905fbeb15dfaf06d1de5ae6253d63a6a2120b31a5da5dabc9550600e20f27d3739e2627925fea3cc509f21dff04e6eea4549c540d6809ff9307eede91fff58733d8385a237d6d3705a33e391900992070df7adf1357cf7e3700ce3667de83f17b8df1778db381dce09cb4ad058a511001a738198ee27cf55a13b754539906582ec8b174bd58d5d1f3d767c613721ae05"),
 			E: 0x010001,
 		},
@@ -3322,7 +3322,7 @@ func TestPSSSign_10_1_45(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -3336,7 +3336,7 @@ func TestPSSSign_10_1_45(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -3371,7 +3371,7 @@ func TestPSSSign_10_1_45(t *testing.T) {
 
 func TestPSSSign_10_2_46(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("a5dd867ac4cb02f90b9457d48c14a770ef991c56c39c0ec65fd11afa8937cea57b9be7ac73b45c0017615b82d622e318753b6027c0fd157be12f8090fee2a7adcd0eef759f88ba4997c7a42d58c9aa12cb99ae001fe521c13bb5431445a8d5ae4f5e4c7e948ac227d3604071f20e577e905fbeb15dfaf06d1de5ae6253d63a6a2120b31a5da5dabc9550600e20f27d3739e2627925fea3cc509f21dff04e6eea4549c540d6809ff9307eede91fff58733d8385a237d6d3705a33e391900992070df7adf1357cf7e3700ce3667de83f17b8df1778db381dce09cb4ad058a511001a738198ee27cf55a13b754539906582ec8b174bd58d5d1f3d767c613721ae05"),
 			E: 0x010001,
 		},
@@ -3395,7 +3395,7 @@ func TestPSSSign_10_2_46(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -3409,7 +3409,7 @@ func TestPSSSign_10_2_46(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -3444,7 +3444,7 @@ func TestPSSSign_10_2_46(t *testing.T) {
 
 func TestPSSSign_10_3_47(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("a5dd867ac4cb02f90b9457d48c14a770ef991c56c39c0ec65fd11afa8937cea57b9be7ac73b45c0017615b82d622e318753b6027c0fd157be12f8090fee2a7adcd0eef759f88ba4997c7a42d58c9aa12cb99ae001fe521c13bb5431445a8d5ae4f5e4c7e948ac227d3604071f20e577e905fbeb15dfaf06d1de5ae6253d63a6a2120b31a5da5dabc9550600e20f27d3739e2627925fea3cc509f21dff04e6eea4549c540d6809ff9307eede91fff58733d8385a237d6d3705a33e391900992070df7adf1357cf7e3700ce3667de83f17b8df1778db381dce09cb4ad058a511001a738198ee27cf55a13b754539906582ec8b174bd58d5d1f3d767c613721ae05"),
 			E: 0x010001,
 		},
@@ -3468,7 +3468,7 @@ func TestPSSSign_10_3_47(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -3482,7 +3482,7 @@ func TestPSSSign_10_3_47(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -3517,7 +3517,7 @@ func TestPSSSign_10_3_47(t *testing.T) {
 
 func TestPSSSign_10_4_48(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("a5dd867ac4cb02f90b9457d48c14a770ef991c56c39c0ec65fd11afa8937cea57b9be7ac73b45c0017615b82d622e318753b6027c0fd157be12f8090fee2a7adcd0eef759f88ba4997c7a42d58c9aa12cb99ae001fe521c13bb5431445a8d5ae4f5e4c7e948ac227d3604071f20e577e905fbeb15dfaf06d1de5ae6253d63a6a2120b31a5da5dabc9550600e20f27d3739e2627925fea3cc509f21dff04e6eea4549c540d6809ff9307eede91fff58733d8385a237d6d3705a33e391900992070df7adf1357cf7e3700ce3667de83f17b8df1778db381dce09cb4ad058a511001a738198ee27cf55a13b754539906582ec8b174bd58d5d1f3d767c613721ae05"),
 			E: 0x010001,
 		},
@@ -3541,7 +3541,7 @@ func TestPSSSign_10_4_48(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -3555,7 +3555,7 @@ func TestPSSSign_10_4_48(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -3590,7 +3590,7 @@ func TestPSSSign_10_4_48(t *testing.T) {
 
 func TestPSSSign_10_5_49(t *testing.T) {
 	priv := &rsa.PrivateKey{
-		rsa.PublicKey: rsa.PublicKey{
+		PublicKey: rsa.PublicKey{
 			N: fromBase16("a5dd867ac4cb02f90b9457d48c14a770ef991c56c39c0ec65fd11afa8937cea57b9be7ac73b45c0017615b82d622e318753b6027c0fd157be12f8090fee2a7adcd0eef759f88ba4997c7a42d58c9aa12cb99ae001fe521c13bb5431445a8d5ae4f5e4c7e948ac227d3604071f20e577e905fbeb15dfaf06d1de5ae6253d63a6a2120b31a5da5dabc9550600e20f27d3739e2627925fea3cc509f21dff04e6eea4549c540d6809ff9307eede91fff58733d8385a237d6d3705a33e391900992070df7adf1357cf7e3700ce3667de83f17b8df1778db381dce09cb4ad058a511001a738198ee27cf55a13b754539906582ec8b174bd58d5d1f3d767c613721ae05"),
 			E: 0x010001,
 		},
@@ -3614,7 +3614,7 @@ func TestPSSSign_10_5_49(t *testing.T) {
 		t.Error("Error: ", err)
 	}
 	if !compareBytes(s, sig) {
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM:\n", em, "\n------\n")
 		fmt.Print("Siglen = ", len(s))
 		fmt.Print("Siglen should be ", len(sig))
@@ -3628,7 +3628,7 @@ func TestPSSSign_10_5_49(t *testing.T) {
 		emBits := priv.N.BitLen() - 1
 		emLen := (emBits + 7) / 8
 		fmt.Print("EM: (len=", len(m.Bytes()), ")\n", m.Bytes(), "\n------\n")
-		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New())
+		em, _ := emsaPSSEncode(hashed, priv.N.BitLen()-1, salt, crypto.SHA1.New, newOptions(nil))
 		fmt.Print("EM should be: (len=", len(em), "; emLen=", emLen, ")\n", em, "\n------\n")
 		t.Errorf("Bad Verification")
 	}
@@ -0,0 +1,14 @@
+//go:build !pssnocryptorand
+
+package pss
+
+import (
+	"crypto/rand"
+	"io"
+	"math/big"
+)
+
+// randInt returns a uniform random value in [0, max) read from random.
+func randInt(random io.Reader, max *big.Int) (*big.Int, error) {
+	return rand.Int(random, max)
+}
@@ -0,0 +1,45 @@
+//go:build pssnocryptorand
+
+package pss
+
+import (
+	"io"
+	"math/big"
+)
+
+// randInt returns a uniform random value in [0, max) read from random. This
+// version is used with the pssnocryptorand build tag and does not import
+// crypto/rand; it follows the rejection sampling of crypto/rand.Int.
+func randInt(random io.Reader, max *big.Int) (n *big.Int, err error) {
+	if max.Sign() <= 0 {
+		panic("crypto/rsa: argument to randInt is <= 0")
+	}
+	n = new(big.Int).Sub(max, bigOne)
+	// bitLen is the maximum bit length needed to encode a value < max.
+	bitLen := n.BitLen()
+	if bitLen == 0 {
+		// the only valid result is 0
+		return
+	}
+	// k is the maximum byte length needed to encode a value < max.
+	k := (bitLen + 7) / 8
+	// b is the number of bits in the most significant byte of max-1.
+	b := uint(bitLen % 8)
+	if b == 0 {
+		b = 8
+	}
+
+	bytes := make([]byte, k)
+	for {
+		if _, err = io.ReadFull(random, bytes); err != nil {
+			return nil, err
+		}
+		// Clear bits in the first byte to increase the probability
+		// that the candidate is < max.
+		bytes[0] &= uint8(int(1<<b) - 1)
+		n.SetBytes(bytes)
+		if n.Cmp(max) < 0 {
+			return
+		}
+	}
+}
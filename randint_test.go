@@ -0,0 +1,89 @@
+package pss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"io"
+	"math/big"
+	"testing"
+)
+
+// newTestRand returns a deterministic random source for seed. Passing it as
+// the rand argument of SignPSS together with a fixed salt makes the whole
+// signing operation reproducible, including the blinding factor, while still
+// going through the blinding code:
+//
+//	sig, err := SignPSS(newTestRand("name of the test"), priv, hash, hashed, salt)
+//
+// It must never be used outside of tests.
+func newTestRand(seed string) io.Reader {
+	return NewMGF1Reader(sha256.New(), []byte(seed))
+}
+
+// countingReader counts the calls to Read and the bytes read from r.
+type countingReader struct {
+	r     io.Reader
+	n     int
+	calls int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	c.calls++
+	return n, err
+}
+
+func TestRandInt(t *testing.T) {
+	for _, max := range []int64{1, 2, 255, 256, 257, 1 << 40} {
+		m := big.NewInt(max)
+		for i := 0; i < 50; i++ {
+			n, err := randInt(rand.Reader, m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n.Sign() < 0 || n.Cmp(m) >= 0 {
+				t.Fatalf("randInt(%d) = %v, out of range", max, n)
+			}
+		}
+	}
+}
+
+func TestTestRand(t *testing.T) {
+	a := make([]byte, 100)
+	b := make([]byte, 100)
+	io.ReadFull(newTestRand("seed"), a)
+	io.ReadFull(newTestRand("seed"), b)
+	if !bytes.Equal(a, b) {
+		t.Errorf("same seed gave different output")
+	}
+	io.ReadFull(newTestRand("other seed"), b)
+	if bytes.Equal(a, b) {
+		t.Errorf("different seeds gave the same output")
+	}
+
+	key := selfTestKey()
+	hashed := sha1.Sum(mustDecodeHex(selfTestMsg))
+	salt := mustDecodeHex(selfTestSalt)
+	var consumed []int
+	for i := 0; i < 2; i++ {
+		r := &countingReader{r: newTestRand("TestTestRand")}
+		sig, err := SignPSS(r, key, crypto.SHA1, hashed[:], salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !compareBytes(sig, mustDecodeHex(selfTestSig)) {
+			t.Errorf("signature does not match the known answer")
+		}
+		if r.n == 0 {
+			t.Errorf("blinding did not read from the random source")
+		}
+		consumed = append(consumed, r.n)
+	}
+	if consumed[0] != consumed[1] {
+		t.Errorf("runs read %d and %d random bytes", consumed[0], consumed[1])
+	}
+}
@@ -0,0 +1,42 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"io"
+	"math/big"
+)
+
+// RawEncrypt computes m^e mod n, the RSA public key operation, and returns
+// the result in a new big.Int.
+//
+// This is textbook RSA with no padding. It is insecure on its own and must
+// not be applied directly to messages; it is exported for building and
+// teaching other RSA schemes.
+func RawEncrypt(pub *rsa.PublicKey, m *big.Int) *big.Int {
+	return encrypt(new(big.Int), pub, m)
+}
+
+// RawDecrypt computes c^d mod n, the RSA private key operation, using the
+// CRT values of priv when present. rand is used for RSA blinding; it may
+// only be nil if WithoutBlinding is among the options.
+//
+// This is textbook RSA with no padding. It is insecure on its own and must
+// not be applied directly to messages; it is exported for building and
+// teaching other RSA schemes.
+func RawDecrypt(rand io.Reader, priv *rsa.PrivateKey, c *big.Int, opts ...Option) (*big.Int, error) {
+	return decrypt(rand, priv, c, newOptions(opts))
+}
+
+// ComputeExpectedEM returns the encoded message EM that signing hashed with
+// hash and salt would produce for a key with the modulus of pub; pub is used
+// only for the bit length of its modulus. When a signature does not verify,
+// EM can be compared with RawEncrypt of the signature, left-padded to the
+// same length, to see which part of the encoding differs.
+func ComputeExpectedEM(pub *rsa.PublicKey, hash crypto.Hash, hashed []byte, salt []byte, opts ...Option) ([]byte, error) {
+	h := cryptoHashFunc(hash)
+	if err := h.check(hashed); err != nil {
+		return nil, err
+	}
+	return emsaPSSEncode(hashed, pub.N.BitLen()-1, salt, h.new, newOptions(opts))
+}
@@ -0,0 +1,74 @@
+package pss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+)
+
+func TestRawEncryptDecrypt(t *testing.T) {
+	priv := testKey(t)
+	for i := 0; i < 4; i++ {
+		m, err := rand.Int(rand.Reader, priv.N)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := RawEncrypt(&priv.PublicKey, m)
+		got, err := RawDecrypt(rand.Reader, priv, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Cmp(m) != 0 {
+			t.Errorf("round trip of %x gave %x", m, got)
+		}
+		got, err = RawDecrypt(nil, priv, c, WithoutBlinding())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Cmp(m) != 0 {
+			t.Errorf("unblinded round trip of %x gave %x", m, got)
+		}
+		if _, err := RawDecrypt(nil, priv, c); err != errNilRandom {
+			t.Errorf("nil random source without WithoutBlinding: got %v, want errNilRandom", err)
+		}
+	}
+}
+
+func TestRawDecryptRange(t *testing.T) {
+	priv := testKey(t)
+	for _, c := range []*big.Int{priv.N, new(big.Int).Add(priv.N, bigOne)} {
+		if _, err := RawDecrypt(nil, priv, c); err != rsa.ErrDecryption {
+			t.Errorf("c = N + %v: got %v, want rsa.ErrDecryption", new(big.Int).Sub(c, priv.N), err)
+		}
+	}
+	below := new(big.Int).Sub(priv.N, bigOne)
+	if _, err := RawDecrypt(nil, priv, below, WithoutBlinding()); err != nil {
+		t.Errorf("c = N - 1: %v", err)
+	}
+}
+
+func TestComputeExpectedEM(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	hashed := sha256.Sum256([]byte("expected EM"))
+	salt := []byte("fixed salt")
+	sig, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	em, err := ComputeExpectedEM(pub, crypto.SHA256, hashed[:], salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovered := RawEncrypt(pub, new(big.Int).SetBytes(sig)).FillBytes(make([]byte, len(em)))
+	if !bytes.Equal(em, recovered) {
+		t.Errorf("expected EM differs from the one in the signature")
+	}
+	if _, err := ComputeExpectedEM(pub, crypto.SHA256, hashed[:20], salt); err == nil {
+		t.Errorf("short digest accepted")
+	}
+}
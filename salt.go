@@ -0,0 +1,268 @@
+package pss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// maxSaltAttempts bounds how many times a salt rejected by the salt check is
+// regenerated before signing gives up.
+const maxSaltAttempts = 3
+
+// ErrSaltRejected is returned when every salt generated for a signature was
+// rejected by the check installed with WithSaltCheck.
+var ErrSaltRejected = errors.New("crypto/rsa: generated salt rejected by salt check")
+
+var errNegativeSaltLength = errors.New("crypto/rsa: negative salt length")
+
+// WithSaltCheck installs a predicate that is called on every salt generated
+// by SignPSSAutoSalt. A salt for which check returns false is discarded and
+// a new one is read from the random source; after a few rejected salts
+// signing fails with ErrSaltRejected.
+func WithSaltCheck(check func(salt []byte) bool) Option {
+	return func(o *options) {
+		o.saltCheck = check
+	}
+}
+
+// NewSaltSanityCheck returns a salt check for WithSaltCheck that rejects an
+// all-zero salt and a salt identical to the one it saw last. It cannot
+// detect a weak random source, only one that has failed outright. The
+// returned function is safe for concurrent use.
+func NewSaltSanityCheck() func(salt []byte) bool {
+	var mu sync.Mutex
+	var last []byte
+	return func(salt []byte) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(salt) == 0 {
+			return true
+		}
+		zero := true
+		for _, b := range salt {
+			if b != 0 {
+				zero = false
+				break
+			}
+		}
+		if zero || bytes.Equal(salt, last) {
+			return false
+		}
+		last = append(last[:0], salt...)
+		return true
+	}
+}
+
+// A SaltSource returns the salt of saltLen bytes for a signature.
+type SaltSource func(saltLen int) ([]byte, error)
+
+// WithSaltSource makes the signing functions that generate a salt, such as
+// SignPSSAutoSalt, take it from src instead of reading it from the random
+// source, which is then used for blinding only. A salt check installed with
+// WithSaltCheck still applies. It replaces a WithDigestSaltSource given
+// before it.
+func WithSaltSource(src SaltSource) Option {
+	return func(o *options) {
+		o.saltSource = func(hashed []byte, saltLen int) ([]byte, error) {
+			return src(saltLen)
+		}
+	}
+}
+
+// A DigestSaltSource returns the salt of saltLen bytes for a signature of
+// the digest hashed.
+type DigestSaltSource func(hashed []byte, saltLen int) ([]byte, error)
+
+// WithDigestSaltSource is like WithSaltSource but lets the salt depend on
+// the digest being signed. The streaming functions only call src once the
+// whole message has been hashed, so SignPSSStream can sign deterministically
+// with a salt that cannot be known before the stream ends. It replaces a
+// WithSaltSource given before it.
+func WithDigestSaltSource(src DigestSaltSource) Option {
+	return func(o *options) {
+		o.saltSource = src
+	}
+}
+
+// CounterSaltSource returns a SaltSource that derives the salt from counter,
+// a value the caller never repeats for key, such as a monotonic signature
+// counter. The salt is the leading saltLen bytes of
+//
+//	HMAC(key, counter || 0) || HMAC(key, counter || 1) || ...
+//
+// with hash as the HMAC hash, counter as 8 bytes and the block number as 4
+// bytes, both big-endian. The salts are distinct for distinct counters and
+// can be reproduced from the counter without a random source. Reusing a
+// counter reuses its salt, which makes signatures of the same message equal
+// but does not otherwise weaken them.
+func CounterSaltSource(hash crypto.Hash, key []byte, counter uint64) SaltSource {
+	return func(saltLen int) ([]byte, error) {
+		var c [8]byte
+		binary.BigEndian.PutUint64(c[:], counter)
+		return hmacSalt(hash, key, c[:], saltLen)
+	}
+}
+
+// HMACDigestSaltSource returns a DigestSaltSource that derives the salt from
+// the digest like CounterSaltSource does from its counter, as the leading
+// saltLen bytes of
+//
+//	HMAC(key, hashed || 0) || HMAC(key, hashed || 1) || ...
+//
+// The same digest always gets the same salt, so signing a message twice
+// gives the same signature, and without key the salt cannot be predicted
+// from the message.
+func HMACDigestSaltSource(hash crypto.Hash, key []byte) DigestSaltSource {
+	return func(hashed []byte, saltLen int) ([]byte, error) {
+		return hmacSalt(hash, key, hashed, saltLen)
+	}
+}
+
+// hmacSalt returns the leading saltLen bytes of the HMACs of prefix
+// followed by the block numbers 0, 1, ... as 4 byte big-endian integers.
+func hmacSalt(hash crypto.Hash, key, prefix []byte, saltLen int) ([]byte, error) {
+	if !hash.Available() {
+		return nil, fmt.Errorf("crypto/rsa: hash function %v not available", hash)
+	}
+	mac := hmac.New(hash.New, key)
+	var block [4]byte
+	salt := make([]byte, 0, saltLen+mac.Size())
+	for i := uint32(0); len(salt) < saltLen; i++ {
+		binary.BigEndian.PutUint32(block[:], i)
+		mac.Reset()
+		mac.Write(prefix)
+		mac.Write(block[:])
+		salt = mac.Sum(salt)
+	}
+	return salt[:saltLen], nil
+}
+
+// SignPSSAutoSalt is like SignPSS but reads a salt of saltLen bytes from
+// rand instead of taking one from the caller.
+func SignPSSAutoSalt(rand io.Reader, priv *rsa.PrivateKey, hash crypto.Hash, hashed []byte, saltLen int, opts ...Option) ([]byte, error) {
+	o := newOptions(opts)
+	salt, err := generateSalt(rand, hashed, saltLen, o)
+	if err != nil {
+		return nil, err
+	}
+	return signPSS(rand, priv, hash, hashed, salt, o)
+}
+
+// generateSalt returns a salt of saltLen bytes for a signature of hashed,
+// read from rand or taken from the salt source of o.
+func generateSalt(rand io.Reader, hashed []byte, saltLen int, o *options) ([]byte, error) {
+	if saltLen < 0 {
+		return nil, errNegativeSaltLength
+	}
+	if rand == nil && o.saltSource == nil {
+		return nil, errNilRandom
+	}
+	salt := make([]byte, saltLen)
+	for i := 0; i < maxSaltAttempts; i++ {
+		if o.saltSource != nil {
+			s, err := o.saltSource(hashed, saltLen)
+			if err != nil {
+				return nil, err
+			}
+			if len(s) != saltLen {
+				return nil, fmt.Errorf("crypto/rsa: salt source returned %d bytes, want %d", len(s), saltLen)
+			}
+			copy(salt, s)
+		} else if _, err := io.ReadFull(rand, salt); err != nil {
+			return nil, err
+		}
+		if o.saltCheck == nil || o.saltCheck(salt) {
+			return salt, nil
+		}
+	}
+	return nil, ErrSaltRejected
+}
+
+// VerifyAndExtract verifies sig as VerifyPSS does and, only if it is valid,
+// returns its salt. It is for the non-standard use of the salt to carry
+// application data: since anyone can recover the salt, whether or not the
+// signature is valid, the data can only be trusted after verification, and
+// coupling the two keeps a caller from reading it from a forged signature.
+// sLen may be SaltLengthAuto.
+func VerifyAndExtract(pub *rsa.PublicKey, hash crypto.Hash, hashed []byte, sig []byte, sLen int, opts ...Option) (data []byte, err error) {
+	o := newOptions(opts)
+	o.saltLengthFound = func(n int) { sLen = n }
+	sc := new(verifyScratch)
+	if err := verifyPSS(pub, hash.New, hashed, sig, sLen, sc, o); err != nil {
+		return nil, err
+	}
+	// On success sc.em holds the unmasked data block, which ends in the
+	// salt, followed by H and the trailer.
+	emLen := (pub.N.BitLen() - 1 + 7) / 8
+	db := sc.em[:emLen-hash.Size()-len(o.trailerField())]
+	return append([]byte(nil), db[len(db)-sLen:]...), nil
+}
+
+// DefaultSaltLength returns the salt length that crypto/rsa.SignPSS uses when
+// it is given nil options or rsa.PSSSaltLengthAuto: the largest salt that
+// fits the key,
+//
+//	(pub.N.BitLen()-1+7)/8 - 2 - hash.Size()
+//
+// Signing with SignPSSAutoSalt and this length produces signatures with the
+// same parameters as the standard library's default. It returns
+// InvalidSaltLength where MaxSaltLength does.
+func DefaultSaltLength(pub *rsa.PublicKey, hash crypto.Hash) int {
+	return MaxSaltLength(pub, hash)
+}
+
+// InvalidSaltLength is returned by MaxSaltLength and DefaultSaltLength when
+// no salt fits. Signing and verification reject it as a salt length; unlike
+// SaltLengthAuto it never makes verification accept any salt.
+const InvalidSaltLength = -2
+
+// MaxSaltLength returns the length of the longest salt that a signature
+// with pub, hash and the default trailer can have. Signing with a longer
+// salt fails with an error that names both lengths. If pub has no modulus,
+// hash is not available or the key is too small for the hash, as
+// IsValidPSSConfig reports for an empty salt, it returns InvalidSaltLength.
+func MaxSaltLength(pub *rsa.PublicKey, hash crypto.Hash) int {
+	if pub == nil || pub.N == nil || IsValidPSSConfig(pub.N.BitLen(), hash, 0) != nil {
+		return InvalidSaltLength
+	}
+	return (pub.N.BitLen()-1+7)/8 - 2 - hash.Size()
+}
+
+// recommendedSaltHashes lists the hash functions that RecommendedSaltLength
+// knows about.
+var recommendedSaltHashes = map[crypto.Hash]bool{
+	crypto.SHA1:       true,
+	crypto.SHA224:     true,
+	crypto.SHA256:     true,
+	crypto.SHA384:     true,
+	crypto.SHA512:     true,
+	crypto.SHA512_224: true,
+	crypto.SHA512_256: true,
+	crypto.SHA3_224:   true,
+	crypto.SHA3_256:   true,
+	crypto.SHA3_384:   true,
+	crypto.SHA3_512:   true,
+}
+
+// RecommendedSaltLength returns the salt length to use with hash: the size of
+// its output. RFC 3447 and FIPS 186-4 bound the salt by the hash length and
+// the security proof of PSS gains nothing from a longer salt, and this is
+// the length most other implementations expect, for example the PS256, PS384
+// and PS512 algorithms of JWA. DefaultSaltLength, by contrast, returns the
+// largest salt a key can take.
+//
+// RecommendedSaltLength returns -1 for hash functions it has no
+// recommendation for.
+func RecommendedSaltLength(hash crypto.Hash) int {
+	if !recommendedSaltHashes[hash] {
+		return -1
+	}
+	return hash.Size()
+}
@@ -0,0 +1,296 @@
+package pss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
+func TestSignPSSAutoSalt(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("auto salt"))
+	sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyPSS(&priv.PublicKey, crypto.SHA256, hashed[:], sig, sha256.Size); err != nil {
+		t.Errorf("verify error: %v", err)
+	}
+}
+
+func TestSignPSSAutoSaltNilRandom(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("nil random"))
+	noBlinding := WithoutBlinding()
+	for name, sign := range map[string]func() ([]byte, error){
+		"SignPSSAutoSalt": func() ([]byte, error) {
+			return SignPSSAutoSalt(nil, priv, crypto.SHA256, hashed[:], sha256.Size, noBlinding)
+		},
+		"SignPSSStream": func() ([]byte, error) {
+			return SignPSSStream(nil, priv, crypto.SHA256, strings.NewReader("message"), sha256.Size, noBlinding)
+		},
+		"SignPSSWithMetadata": func() ([]byte, error) {
+			sig, _, err := SignPSSWithMetadata(nil, priv, crypto.SHA256, hashed[:], sha256.Size, noBlinding)
+			return sig, err
+		},
+		"SignPSSWithOpts": func() ([]byte, error) {
+			return SignPSSWithOpts(nil, priv, hashed[:], PS256(), noBlinding)
+		},
+		"SignManifest": func() ([]byte, error) {
+			return SignManifest(nil, priv, crypto.SHA256, map[string][]byte{"a": hashed[:]}, sha256.Size, noBlinding)
+		},
+		"SignMerkleRoot": func() ([]byte, error) {
+			return SignMerkleRoot(nil, priv, crypto.SHA256, [][]byte{hashed[:]}, sha256.Size, noBlinding)
+		},
+		"KeyRing.Sign": func() ([]byte, error) {
+			r := NewKeyRing()
+			if err := r.Add("k", priv); err != nil {
+				return nil, err
+			}
+			sig, _, err := r.Sign(nil, crypto.SHA256, hashed[:], sha256.Size, noBlinding)
+			return sig, err
+		},
+	} {
+		if sig, err := sign(); err != errNilRandom || sig != nil {
+			t.Errorf("%s: got %x, %v; want errNilRandom", name, sig, err)
+		}
+	}
+
+	// A salt source makes the random source unnecessary.
+	src := WithSaltSource(CounterSaltSource(crypto.SHA256, []byte("key"), 1))
+	sig, err := SignPSSAutoSalt(nil, priv, crypto.SHA256, hashed[:], sha256.Size, noBlinding, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyPSS(&priv.PublicKey, crypto.SHA256, hashed[:], sig, sha256.Size); err != nil {
+		t.Errorf("signature with a salt source: %v", err)
+	}
+}
+
+func TestSaltSanityCheck(t *testing.T) {
+	check := NewSaltSanityCheck()
+	if check(make([]byte, 16)) {
+		t.Errorf("all-zero salt accepted")
+	}
+	salt := bytes.Repeat([]byte{0x5a}, 16)
+	if !check(salt) {
+		t.Errorf("fresh salt rejected")
+	}
+	if check(salt) {
+		t.Errorf("repeated salt accepted")
+	}
+
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("zero salt"))
+	_, err := SignPSSAutoSalt(zeroReader{}, priv, crypto.SHA256, hashed[:], sha256.Size, WithSaltCheck(NewSaltSanityCheck()))
+	if err != ErrSaltRejected {
+		t.Errorf("got %v, want ErrSaltRejected", err)
+	}
+	_, err = SignPSSAutoSalt(zeroReader{}, priv, crypto.SHA256, hashed[:], sha256.Size)
+	if err != nil {
+		t.Errorf("without a salt check: %v", err)
+	}
+}
+
+func TestDefaultSaltLength(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("stdlib default"))
+	sLen := DefaultSaltLength(&priv.PublicKey, crypto.SHA256)
+	if want := 256 - 2 - 32; sLen != want {
+		t.Fatalf("got %d, want %d", sLen, want)
+	}
+
+	sig, err := rsa.SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyPSS(&priv.PublicKey, crypto.SHA256, hashed[:], sig, sLen); err != nil {
+		t.Errorf("stdlib default signature does not verify with DefaultSaltLength: %v", err)
+	}
+
+	sig, err = SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sLen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := &rsa.PSSOptions{SaltLength: sLen}
+	if err := rsa.VerifyPSS(&priv.PublicKey, crypto.SHA256, hashed[:], sig, opts); err != nil {
+		t.Errorf("stdlib rejects signature made with DefaultSaltLength: %v", err)
+	}
+}
+
+func TestRecommendedSaltLength(t *testing.T) {
+	tests := []struct {
+		hash crypto.Hash
+		want int
+	}{
+		{crypto.SHA1, 20},
+		{crypto.SHA256, 32},
+		{crypto.SHA384, 48},
+		{crypto.SHA512, 64},
+		{crypto.SHA3_256, 32},
+		{crypto.MD5, -1},
+		{0, -1},
+	}
+	for _, tt := range tests {
+		if got := RecommendedSaltLength(tt.hash); got != tt.want {
+			t.Errorf("RecommendedSaltLength(%v) = %d, want %d", tt.hash, got, tt.want)
+		}
+	}
+}
+
+func TestSignPSSSaltTooLong(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("salt too long"))
+	max := MaxSaltLength(&priv.PublicKey, crypto.SHA256)
+	if _, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], max); err != nil {
+		t.Errorf("maximum salt length: %v", err)
+	}
+	_, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], max+1)
+	if err == nil {
+		t.Fatal("salt longer than the maximum accepted")
+	}
+	if want := fmt.Sprintf("salt length %d exceeds maximum %d", max+1, max); !strings.Contains(err.Error(), want) {
+		t.Errorf("got error %q, want it to mention %q", err, want)
+	}
+
+	small := &rsa.PublicKey{N: new(big.Int).Lsh(bigOne, 8*sha512.Size), E: 65537}
+	for _, test := range []struct {
+		pub  *rsa.PublicKey
+		hash crypto.Hash
+	}{
+		{&priv.PublicKey, 0},
+		{&priv.PublicKey, crypto.MD4},
+		{&priv.PublicKey, crypto.Hash(200)},
+		{small, crypto.SHA512},
+		{nil, crypto.SHA256},
+	} {
+		if got := MaxSaltLength(test.pub, test.hash); got != InvalidSaltLength {
+			t.Errorf("MaxSaltLength with %v: got %d, want InvalidSaltLength", test.hash, got)
+		}
+	}
+	if err := VerifyPSS(small, crypto.SHA512, make([]byte, sha512.Size), make([]byte, sha512.Size+1), MaxSaltLength(small, crypto.SHA512)); err != errNegativeSaltLength {
+		t.Errorf("verifying with InvalidSaltLength: got %v, want errNegativeSaltLength", err)
+	}
+
+	// A modulus too small for the hash reports the plain encoding error.
+	_, err = emsaPSSEncode(make([]byte, sha256.Size), 8*sha256.Size, nil, sha256.New, newOptions(nil))
+	if err == nil || strings.Contains(err.Error(), "salt length") {
+		t.Errorf("modulus too small for the hash: got %v", err)
+	}
+}
+
+// TestSignPSSAutoSaltShortReads checks that the salt is assembled from a
+// random source that returns one byte per read, and that signing fails when
+// the source ends before the salt is complete.
+func TestSignPSSAutoSaltShortReads(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("short reads"))
+	want := make([]byte, 32)
+	io.ReadFull(newTestRand("salt"), want)
+
+	sig, meta, err := SignPSSWithMetadata(iotest.OneByteReader(newTestRand("salt")), priv, crypto.SHA256, hashed[:], len(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(meta.Salt, want) {
+		t.Errorf("salt %x, want %x", meta.Salt, want)
+	}
+	if err := VerifyPSS(&priv.PublicKey, crypto.SHA256, hashed[:], sig, len(want)); err != nil {
+		t.Errorf("signature with a salt read byte by byte: %v", err)
+	}
+
+	short := iotest.OneByteReader(bytes.NewReader(want[:31]))
+	if _, err := SignPSSAutoSalt(short, priv, crypto.SHA256, hashed[:], len(want)); err != io.ErrUnexpectedEOF {
+		t.Errorf("salt source ending early: got %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestVerifyAndExtract(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	hashed := sha256.Sum256([]byte("data in the salt"))
+	data := []byte("application data")
+	sig, err := SignPSS(rand.Reader, priv, crypto.SHA256, hashed[:], data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sLen := range []int{len(data), SaltLengthAuto} {
+		got, err := VerifyAndExtract(pub, crypto.SHA256, hashed[:], sig, sLen)
+		if err != nil {
+			t.Fatalf("sLen %d: %v", sLen, err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("sLen %d: extracted %q, want %q", sLen, got, data)
+		}
+	}
+
+	other := sha256.Sum256([]byte("another message"))
+	if got, err := VerifyAndExtract(pub, crypto.SHA256, other[:], sig, len(data)); err != rsa.ErrVerification || got != nil {
+		t.Errorf("wrong digest: got %q, %v; want nil, rsa.ErrVerification", got, err)
+	}
+}
+
+func TestCounterSaltSource(t *testing.T) {
+	key := []byte("salt key")
+	salt, err := CounterSaltSource(crypto.SHA256, key, 1)(40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0})
+	want := mac.Sum(nil)
+	mac.Reset()
+	mac.Write([]byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 1})
+	want = mac.Sum(want)[:40]
+	if !bytes.Equal(salt, want) {
+		t.Errorf("salt %x, want %x", salt, want)
+	}
+
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("counter salt"))
+	sign := func(counter uint64) ([]byte, PSSMeta) {
+		src := WithSaltSource(CounterSaltSource(crypto.SHA256, key, counter))
+		sig, meta, err := SignPSSWithMetadata(rand.Reader, priv, crypto.SHA256, hashed[:], 32, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sig, meta
+	}
+	sig1, meta := sign(1)
+	if !bytes.Equal(meta.Salt, want[:32]) {
+		t.Errorf("signature salt %x, want %x", meta.Salt, want[:32])
+	}
+	if again, _ := sign(1); !bytes.Equal(again, sig1) {
+		t.Errorf("same counter gives a different signature")
+	}
+	if other, _ := sign(2); bytes.Equal(other, sig1) {
+		t.Errorf("different counters give the same signature")
+	}
+	if err := VerifyPSS(&priv.PublicKey, crypto.SHA256, hashed[:], sig1, 32); err != nil {
+		t.Error(err)
+	}
+
+	short := WithSaltSource(func(n int) ([]byte, error) { return make([]byte, n-1), nil })
+	if _, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], 32, short); err == nil {
+		t.Errorf("short salt from the salt source accepted")
+	}
+}
@@ -0,0 +1,96 @@
+package pss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// The known answer used by SelfTest is example 1.1 of the RSASSA-PSS test
+// vectors from RSA Laboratories: a 1024 bit key, SHA-1 and a 20 byte salt.
+const (
+	selfTestN = "a56e4a0e701017589a5187dc7ea841d156f2ec0e36ad52a44dfeb1e61f7ad991" +
+		"d8c51056ffedb162b4c0f283a12a88a394dff526ab7291cbb307ceabfce0b1df" +
+		"d5cd9508096d5b2b8b6df5d671ef6377c0921cb23c270a70e2598e6ff89d19f1" +
+		"05acc2d3f0cb35f29280e1386b6f64c4ef22e1e1f20d0ce8cffb2249bd9a2137"
+	selfTestD = "33a5042a90b27d4f5451ca9bbbd0b44771a101af884340aef9885f2a4bbe92e8" +
+		"94a724ac3c568c8f97853ad07c0266c8c6a3ca0929f1e8f11231884429fc4d9a" +
+		"e55fee896a10ce707c3ed7e734e44727a39574501a532683109c2abacaba283c" +
+		"31b4bd2f53c3ee37e352cee34f9e503bd80c0622ad79c6dcee883547c6a3b325"
+	selfTestP = "e7e8942720a877517273a356053ea2a1bc0c94aa72d55c6e86296b2dfc967948" +
+		"c0a72cbccca7eacb35706e09a1df55a1535bd9b3cc34160b3b6dcd3eda8e6443"
+	selfTestQ = "b69dca1cf7d4d7ec81e75b90fcca874abcde123fd2700180aa90479b6e48de8d" +
+		"67ed24f9f19d85ba275874f542cd20dc723e6963364a1f9425452b269a6799fd"
+	selfTestE = 0x010001
+
+	selfTestMsg = "cdc87da223d786df3b45e0bbbc721326d1ee2af806cc315475cc6f0d9c66e1b6" +
+		"2371d45ce2392e1ac92844c310102f156a0d8d52c1f4c40ba3aa65095786cb76" +
+		"9757a6563ba958fed0bcc984e8b517a3d5f515b23b8a41e74aa867693f90dfb0" +
+		"61a6e86dfaaee64472c00e5f20945729cbebe77f06ce78e08f4098fba41f9d61" +
+		"93c0317e8b60d4b6084acb42d29e3808a3bc372d85e331170fcbf7cc72d0b71c" +
+		"296648b3a4d10f416295d0807aa625cab2744fd9ea8fd223c42537029828bd16" +
+		"be02546f130fd2e33b936d2676e08aed1b73318b750a0167d0"
+	selfTestSalt = "dee959c7e06411361420ff80185ed57f3e6776af"
+	selfTestSig  = "9074308fb598e9701b2294388e52f971faac2b60a5145af185df5287b5ed2887" +
+		"e57ce7fd44dc8634e407c8e0e4360bc226f3ec227f9d9e54638e8d31f5051215" +
+		"df6ebb9c2f9579aa77598a38f914b5b9c1bd83c4e2f9f382a0d0aa3542ffee65" +
+		"984a601bc69eb28deb27dca12c82c2d4c3f66cd500f1ff2b994d8a4e30cbb33c"
+)
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func mustParseHexInt(s string) *big.Int {
+	return new(big.Int).SetBytes(mustDecodeHex(s))
+}
+
+func selfTestKey() *rsa.PrivateKey {
+	return &rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{
+			N: mustParseHexInt(selfTestN),
+			E: selfTestE,
+		},
+		D: mustParseHexInt(selfTestD),
+		Primes: []*big.Int{
+			mustParseHexInt(selfTestP),
+			mustParseHexInt(selfTestQ),
+		},
+	}
+}
+
+// SelfTest signs and verifies a fixed known-answer vector and returns an
+// error if the result differs from the expected one. It is meant to be run
+// as a power-on self-test before any real signing; building with the
+// pssselftest tag runs it from an init function that panics on failure.
+func SelfTest() error {
+	priv := selfTestKey()
+	digest := sha1.Sum(mustDecodeHex(selfTestMsg))
+	salt := mustDecodeHex(selfTestSalt)
+	want := mustDecodeHex(selfTestSig)
+
+	sig, err := SignPSSFixed(priv, crypto.SHA1, digest[:], salt)
+	if err != nil {
+		return fmt.Errorf("crypto/rsa: self-test: signing failed: %w", err)
+	}
+	if !bytes.Equal(sig, want) {
+		return errors.New("crypto/rsa: self-test: signature does not match the known answer")
+	}
+	if err := VerifyPSS(&priv.PublicKey, crypto.SHA1, digest[:], want, len(salt)); err != nil {
+		return fmt.Errorf("crypto/rsa: self-test: known answer does not verify: %w", err)
+	}
+	want[len(want)/2] ^= 0x01
+	if err := VerifyPSS(&priv.PublicKey, crypto.SHA1, digest[:], want, len(salt)); err == nil {
+		return errors.New("crypto/rsa: self-test: corrupted signature verifies")
+	}
+	return nil
+}
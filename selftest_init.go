@@ -0,0 +1,9 @@
+//go:build pssselftest
+
+package pss
+
+func init() {
+	if err := SelfTest(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,197 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+)
+
+var (
+	errSignatureTooShort = errors.New("crypto/rsa: signature shorter than the modulus")
+	errSignatureTooLong  = errors.New("crypto/rsa: signature longer than the modulus")
+)
+
+// VerifyPSSReader is like VerifyPSS but reads the signature from r, for
+// example a detached signature file. r must hold exactly one signature, as
+// many bytes as the modulus, and nothing after it; a short signature is
+// only accepted with WithTolerateMissingLeadingZero. Errors from r are
+// returned as they are.
+func VerifyPSSReader(pub *rsa.PublicKey, hash crypto.Hash, hashed []byte, r io.Reader, sLen int, opts ...Option) error {
+	o := newOptions(opts)
+	if err := o.checkModulusSize(pub.N); err != nil {
+		return err
+	}
+	k := (pub.N.BitLen() + 7) / 8
+	sig := make([]byte, k)
+	n, err := io.ReadFull(r, sig)
+	switch {
+	case err == io.ErrUnexpectedEOF && o.missingLeadingZero && n == k-1:
+		sig = sig[:n]
+	case err == io.ErrUnexpectedEOF || err == io.EOF:
+		return errSignatureTooShort
+	case err != nil:
+		return err
+	}
+	var extra [1]byte
+	if _, err := io.ReadFull(r, extra[:]); err == nil {
+		return errSignatureTooLong
+	} else if err != io.EOF {
+		return err
+	}
+	return verifyPSS(pub, hash.New, hashed, sig, sLen, new(verifyScratch), o)
+}
+
+// A Digester computes the digest of a message written to it. It lets the
+// streaming functions hash the message with an implementation that is not
+// a hash.Hash, such as a hardware accelerator. Only the message goes through
+// the Digester; MGF1 and the hash of the encoding always use the software
+// implementation of the crypto.Hash, so the digest must be the same as the
+// one that hash function computes.
+type Digester interface {
+	// Write adds more of the message. It never returns an error unless the
+	// underlying implementation fails.
+	io.Writer
+	// Sum returns the digest of all data written.
+	Sum() []byte
+	// Size returns the number of bytes Sum will return.
+	Size() int
+}
+
+// hashDigester is the default Digester, backed by a hash.Hash.
+type hashDigester struct {
+	h hash.Hash
+}
+
+func (d hashDigester) Write(p []byte) (int, error) { return d.h.Write(p) }
+func (d hashDigester) Sum() []byte                 { return d.h.Sum(nil) }
+func (d hashDigester) Size() int                   { return d.h.Size() }
+
+// WithDigester makes the streaming functions hash the message with a
+// Digester from newDigester instead of the crypto.Hash they are given.
+func WithDigester(newDigester func() Digester) Option {
+	return func(o *options) {
+		o.newDigester = newDigester
+	}
+}
+
+// defaultProgressInterval is the interval of WithProgress when it is given
+// one that is not positive.
+const defaultProgressInterval = 1 << 20
+
+// WithProgress makes the streaming functions call progress with the number
+// of message bytes hashed so far, each time another interval bytes have
+// been hashed and once more when the whole message has been, for example to
+// drive a progress bar. An interval that is not positive selects 1 MiB.
+// progress runs on the hashing goroutine and only observes the message; it
+// cannot change the digest.
+func WithProgress(interval int64, progress func(processed int64)) Option {
+	if interval <= 0 {
+		interval = defaultProgressInterval
+	}
+	return func(o *options) {
+		o.progressInterval = interval
+		o.progress = progress
+	}
+}
+
+// progressWriter counts the bytes written through it to w and reports them
+// to progress every interval bytes. A large write is split at the interval
+// boundaries, so that every interval is reported however the source
+// writes.
+type progressWriter struct {
+	w        io.Writer
+	n, next  int64
+	reported int64
+	interval int64
+	progress func(int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	written := 0
+	for len(b) > 0 {
+		chunk := b
+		if rest := p.next - p.n; int64(len(chunk)) > rest {
+			chunk = chunk[:rest]
+		}
+		n, err := p.w.Write(chunk)
+		written += n
+		p.n += int64(n)
+		if p.n >= p.next {
+			p.report()
+			p.next = p.n - p.n%p.interval + p.interval
+		}
+		if err != nil {
+			return written, err
+		}
+		if n < len(chunk) {
+			return written, io.ErrShortWrite
+		}
+		b = b[n:]
+	}
+	return written, nil
+}
+
+// report calls progress unless it has already been told about every byte.
+func (p *progressWriter) report() {
+	if p.n != p.reported || p.n == 0 {
+		p.progress(p.n)
+		p.reported = p.n
+	}
+}
+
+// digest hashes message with the Digester of o, or with hash.
+func (o *options) digest(hash crypto.Hash, message io.Reader) ([]byte, error) {
+	var d Digester
+	if o.newDigester != nil {
+		d = o.newDigester()
+	} else {
+		d = hashDigester{hash.New()}
+	}
+	if d.Size() != hash.Size() {
+		return nil, fmt.Errorf("crypto/rsa: digester output is %d bytes, want %d for %v", d.Size(), hash.Size(), hash)
+	}
+	var w io.Writer = d
+	var pw *progressWriter
+	if o.progress != nil {
+		pw = &progressWriter{w: d, next: o.progressInterval, interval: o.progressInterval, progress: o.progress}
+		w = pw
+	}
+	if _, err := io.Copy(w, message); err != nil {
+		return nil, err
+	}
+	if pw != nil {
+		pw.report()
+	}
+	return d.Sum(), nil
+}
+
+// SignPSSStream hashes the message read from message and signs the digest
+// like SignPSSAutoSalt, with a salt of saltLen bytes. The message does not
+// have to fit in memory. The salt is only generated once the message has
+// been hashed, so WithDigestSaltSource can derive it from the digest.
+func SignPSSStream(rand io.Reader, priv *rsa.PrivateKey, hash crypto.Hash, message io.Reader, saltLen int, opts ...Option) ([]byte, error) {
+	o := newOptions(opts)
+	hashed, err := o.digest(hash, message)
+	if err != nil {
+		return nil, err
+	}
+	salt, err := generateSalt(rand, hashed, saltLen, o)
+	if err != nil {
+		return nil, err
+	}
+	return signPSS(rand, priv, hash, hashed, salt, o)
+}
+
+// VerifyPSSStream hashes the message read from message and verifies sig as
+// a signature of the digest like VerifyPSS.
+func VerifyPSSStream(pub *rsa.PublicKey, hash crypto.Hash, message io.Reader, sig []byte, sLen int, opts ...Option) error {
+	o := newOptions(opts)
+	hashed, err := o.digest(hash, message)
+	if err != nil {
+		return err
+	}
+	return verifyPSS(pub, hash.New, hashed, sig, sLen, new(verifyScratch), o)
+}
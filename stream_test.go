@@ -0,0 +1,213 @@
+package pss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestVerifyPSSReader(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	hashed := sha256.Sum256([]byte("signature reader"))
+	sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify := func(r io.Reader, opts ...Option) error {
+		return VerifyPSSReader(pub, crypto.SHA256, hashed[:], r, sha256.Size, opts...)
+	}
+	if err := verify(bytes.NewReader(sig)); err != nil {
+		t.Errorf("valid signature: %v", err)
+	}
+	if err := verify(iotest.OneByteReader(bytes.NewReader(sig))); err != nil {
+		t.Errorf("valid signature, one byte at a time: %v", err)
+	}
+	if err := verify(bytes.NewReader(sig[:len(sig)-1])); err == nil {
+		t.Errorf("short signature accepted")
+	}
+	if err := verify(bytes.NewReader(nil)); err == nil {
+		t.Errorf("empty signature accepted")
+	}
+	if err := verify(bytes.NewReader(append(sig, 0))); err == nil {
+		t.Errorf("signature with trailing data accepted")
+	}
+	if err := verify(iotest.TimeoutReader(bytes.NewReader(append(sig, 0)))); err != iotest.ErrTimeout {
+		t.Errorf("failing reader: got %v, want iotest.ErrTimeout", err)
+	}
+	sig[0] ^= 1
+	if err := verify(bytes.NewReader(sig)); err != rsa.ErrVerification {
+		t.Errorf("bad signature: got %v, want rsa.ErrVerification", err)
+	}
+}
+
+// countingDigester is a Digester that is not a hash.Hash and counts the
+// bytes written to it.
+type countingDigester struct {
+	h hash.Hash
+	n int
+}
+
+func (d *countingDigester) Write(p []byte) (int, error) {
+	d.n += len(p)
+	return d.h.Write(p)
+}
+
+func (d *countingDigester) Sum() []byte { return d.h.Sum(nil) }
+func (d *countingDigester) Size() int   { return d.h.Size() }
+
+func TestSignPSSStream(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	message := bytes.Repeat([]byte("streamed message "), 10000)
+	sig, err := SignPSSStream(rand.Reader, priv, crypto.SHA256, bytes.NewReader(message), sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashed := sha256.Sum256(message)
+	if err := VerifyPSS(pub, crypto.SHA256, hashed[:], sig, sha256.Size); err != nil {
+		t.Errorf("VerifyPSS of a streamed signature: %v", err)
+	}
+	if err := VerifyPSSStream(pub, crypto.SHA256, bytes.NewReader(message), sig, sha256.Size); err != nil {
+		t.Errorf("VerifyPSSStream: %v", err)
+	}
+	if err := VerifyPSSStream(pub, crypto.SHA256, bytes.NewReader(message[1:]), sig, sha256.Size); err != rsa.ErrVerification {
+		t.Errorf("other message: got %v, want rsa.ErrVerification", err)
+	}
+	if err := VerifyPSSStream(pub, crypto.SHA256, iotest.ErrReader(io.ErrClosedPipe), sig, sha256.Size); err != io.ErrClosedPipe {
+		t.Errorf("failing message reader: got %v, want io.ErrClosedPipe", err)
+	}
+
+	var digesters []*countingDigester
+	withDigester := WithDigester(func() Digester {
+		d := &countingDigester{h: sha256.New()}
+		digesters = append(digesters, d)
+		return d
+	})
+	if err := VerifyPSSStream(pub, crypto.SHA256, bytes.NewReader(message), sig, sha256.Size, withDigester); err != nil {
+		t.Errorf("VerifyPSSStream with a digester: %v", err)
+	}
+	sig, err = SignPSSStream(rand.Reader, priv, crypto.SHA256, bytes.NewReader(message), sha256.Size, withDigester)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyPSS(pub, crypto.SHA256, hashed[:], sig, sha256.Size); err != nil {
+		t.Errorf("VerifyPSS of a signature made with a digester: %v", err)
+	}
+	if len(digesters) != 2 {
+		t.Fatalf("%d digesters made, want 2", len(digesters))
+	}
+	for _, d := range digesters {
+		if d.n != len(message) {
+			t.Errorf("digester hashed %d bytes, want %d", d.n, len(message))
+		}
+	}
+
+	wrongSize := WithDigester(func() Digester { return &countingDigester{h: sha512.New()} })
+	if err := VerifyPSSStream(pub, crypto.SHA256, bytes.NewReader(message), sig, sha256.Size, wrongSize); err == nil {
+		t.Errorf("digester of the wrong size accepted")
+	}
+}
+
+func TestSignPSSStreamDigestSalt(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	message := bytes.Repeat([]byte("deterministic stream "), 5000)
+	var calls int
+	src := HMACDigestSaltSource(crypto.SHA256, []byte("salt key"))
+	withSalt := WithDigestSaltSource(func(hashed []byte, saltLen int) ([]byte, error) {
+		calls++
+		return src(hashed, saltLen)
+	})
+	sign := func(message []byte) []byte {
+		t.Helper()
+		r := chunkReader{bytes.NewReader(message), 1000}
+		sig, err := SignPSSStream(rand.Reader, priv, crypto.SHA256, r, sha256.Size, withSalt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sig
+	}
+	sig := sign(message)
+	if !bytes.Equal(sign(message), sig) {
+		t.Errorf("signing the same stream twice gave different signatures")
+	}
+	if calls != 2 {
+		t.Errorf("salt source called %d times, want 2", calls)
+	}
+	hashed := sha256.Sum256(message)
+	salt, err := VerifyAndExtract(pub, crypto.SHA256, hashed[:], sig, sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := src(hashed[:], sha256.Size); !bytes.Equal(salt, want) {
+		t.Errorf("salt %x, want %x", salt, want)
+	}
+
+	other := append([]byte(nil), message...)
+	other[len(other)-1] ^= 1
+	otherSig := sign(other)
+	otherHashed := sha256.Sum256(other)
+	otherSalt, err := VerifyAndExtract(pub, crypto.SHA256, otherHashed[:], otherSig, sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(otherSalt, salt) {
+		t.Errorf("changing the last byte of the message did not change the salt")
+	}
+}
+
+// chunkReader returns at most size bytes per read from r.
+type chunkReader struct {
+	r    io.Reader
+	size int
+}
+
+func (c chunkReader) Read(p []byte) (int, error) {
+	if len(p) > c.size {
+		p = p[:c.size]
+	}
+	return c.r.Read(p)
+}
+
+func TestWithProgress(t *testing.T) {
+	priv := testKey(t)
+	message := make([]byte, 10000)
+	io.ReadFull(newTestRand("progress"), message)
+	var reports []int64
+	progress := WithProgress(3000, func(n int64) { reports = append(reports, n) })
+
+	sig, err := SignPSSStream(rand.Reader, priv, crypto.SHA256, chunkReader{bytes.NewReader(message), 1000}, 32, progress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{3000, 6000, 9000, 10000}; fmt.Sprint(reports) != fmt.Sprint(want) {
+		t.Errorf("progress reports %v, want %v", reports, want)
+	}
+	hashed := sha256.Sum256(message)
+	if err := VerifyPSS(&priv.PublicKey, crypto.SHA256, hashed[:], sig, 32); err != nil {
+		t.Errorf("progress changed the digest: %v", err)
+	}
+
+	reports = nil
+	if err := VerifyPSSStream(&priv.PublicKey, crypto.SHA256, bytes.NewReader(message), sig, 32, progress); err != nil {
+		t.Error(err)
+	}
+	if want := []int64{3000, 6000, 9000, 10000}; fmt.Sprint(reports) != fmt.Sprint(want) {
+		t.Errorf("message written at once: progress reports %v, want %v", reports, want)
+	}
+
+	reports = nil
+	VerifyPSSStream(&priv.PublicKey, crypto.SHA256, bytes.NewReader(nil), sig, 32, progress)
+	if want := []int64{0}; fmt.Sprint(reports) != fmt.Sprint(want) {
+		t.Errorf("empty message: progress reports %v, want %v", reports, want)
+	}
+}
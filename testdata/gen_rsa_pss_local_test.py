@@ -0,0 +1,200 @@
+#!/usr/bin/env python3
+# Generates rsa_pss_local_test.json, a file of RSASSA-PSS verification
+# vectors in the format of Project Wycheproof (rsassa_pss_verify_schema.json).
+#
+# The vectors are not Wycheproof's. EMSA-PSS and RSASP1 are computed here
+# with nothing but the Python standard library, independently of the Go
+# package, and every key and salt comes from a seeded generator, so running
+#
+#	python3 gen_rsa_pss_local_test.py > rsa_pss_local_test.json
+#
+# in this directory reproduces the file byte for byte. The upstream
+# rsa_pss_*_test.json files from https://github.com/C2SP/wycheproof
+# (Apache License 2.0) can be dropped in next to it; TestWycheproofVectors
+# runs every file matching that pattern.
+
+import hashlib
+import json
+import random
+import sys
+
+rng = random.Random("crypto/rsa pss local vectors")
+
+HASH_NAMES = {"sha1": "SHA-1", "sha256": "SHA-256", "sha384": "SHA-384", "sha512": "SHA-512"}
+
+
+def randbytes(n):
+    return bytes(rng.getrandbits(8) for _ in range(n))
+
+
+def is_probable_prime(n):
+    if n < 2:
+        return False
+    for p in (2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37):
+        if n % p == 0:
+            return n == p
+    d, r = n - 1, 0
+    while d % 2 == 0:
+        d, r = d // 2, r + 1
+    for _ in range(40):
+        x = pow(rng.randrange(2, n - 1), d, n)
+        if x in (1, n - 1):
+            continue
+        for _ in range(r - 1):
+            x = pow(x, 2, n)
+            if x == n - 1:
+                break
+        else:
+            return False
+    return True
+
+
+def random_prime(bits, e):
+    while True:
+        p = rng.getrandbits(bits) | (3 << (bits - 2)) | 1
+        if (p - 1) % e != 0 and is_probable_prime(p):
+            return p
+
+
+def generate_key(bits, e=65537):
+    while True:
+        p = random_prime(bits - bits // 2, e)
+        q = random_prime(bits // 2, e)
+        n = p * q
+        if p != q and n.bit_length() == bits:
+            return n, pow(e, -1, (p - 1) * (q - 1))
+
+
+def mgf1(seed, length, h):
+    out = b""
+    counter = 0
+    while len(out) < length:
+        out += hashlib.new(h, seed + counter.to_bytes(4, "big")).digest()
+        counter += 1
+    return out[:length]
+
+
+def encode(mhash, embits, salt, h, mgfh, trailer=b"\xbc", ps_tweak=None, sep=b"\x01", topbit=False, h_tweak=False):
+    """EMSA-PSS-ENCODE of RFC 8017, section 9.1.1, with optional faults."""
+    hlen = hashlib.new(h).digest_size
+    emlen = (embits + 7) // 8
+    H = bytearray(hashlib.new(h, b"\x00" * 8 + mhash + salt).digest())
+    if h_tweak:
+        H[0] ^= 1
+    H = bytes(H)
+    ps = bytearray(emlen - len(salt) - hlen - 1 - len(trailer))
+    if ps_tweak is not None:
+        ps[ps_tweak] = 1
+    db = bytes(ps) + sep + salt
+    masked = bytearray(a ^ b for a, b in zip(db, mgf1(H, len(db), mgfh)))
+    unused = 8 * emlen - embits
+    masked[0] &= 0xFF >> unused
+    if topbit and unused:
+        masked[0] |= 0x80
+    return bytes(masked) + H + trailer
+
+
+class Group:
+    def __init__(self, bits, h, mgfh, slen, weak=False):
+        self.n, self.d = generate_key(bits)
+        self.bits, self.h, self.mgfh, self.slen, self.weak = bits, h, mgfh, slen, weak
+        self.k = (self.n.bit_length() + 7) // 8
+        self.tests = []
+
+    def sign(self, em):
+        return pow(int.from_bytes(em, "big"), self.d, self.n).to_bytes(self.k, "big")
+
+    def add(self, comment, msg, sig, result, flags=()):
+        self.tests.append({"tcId": 0, "comment": comment, "msg": msg.hex(), "sig": sig.hex(), "result": result, "flags": list(flags)})
+
+    def build(self):
+        h, mgfh, slen = self.h, self.mgfh, self.slen
+        embits = self.n.bit_length() - 1
+        for msg in [b"", b"0", b"Test", b"123400", b"Message" * 10]:
+            em = encode(hashlib.new(h, msg).digest(), embits, randbytes(slen), h, mgfh)
+            if self.weak:
+                # A verifier may refuse SHA-1 and 1024-bit keys.
+                self.add("weak parameters", msg, self.sign(em), "acceptable", ["WeakHash"])
+            else:
+                self.add("", msg, self.sign(em), "valid")
+        msg = b"123400"
+        mh = hashlib.new(h, msg).digest()
+        salt = randbytes(slen)
+        self.add("wrong trailer", msg, self.sign(encode(mh, embits, salt, h, mgfh, trailer=b"\xbd")), "invalid", ["ModifiedTrailer"])
+        self.add("nonzero padding", msg, self.sign(encode(mh, embits, salt, h, mgfh, ps_tweak=0)), "invalid", ["ModifiedPadding"])
+        self.add("wrong separator", msg, self.sign(encode(mh, embits, salt, h, mgfh, sep=b"\x02")), "invalid", ["ModifiedPadding"])
+        if embits % 8:
+            self.add("leftmost bit set", msg, self.sign(encode(mh, embits, salt, h, mgfh, topbit=True)), "invalid", ["ModifiedPadding"])
+        self.add("modified H", msg, self.sign(encode(mh, embits, salt, h, mgfh, h_tweak=True)), "invalid", ["ModifiedHash"])
+        self.add("salt one byte longer", msg, self.sign(encode(mh, embits, randbytes(slen + 1), h, mgfh)), "invalid", ["WrongSaltLength"])
+        if slen > 0:
+            self.add("salt one byte shorter", msg, self.sign(encode(mh, embits, randbytes(slen - 1), h, mgfh)), "invalid", ["WrongSaltLength"])
+        other = "sha1" if mgfh != "sha1" else "sha256"
+        self.add("wrong MGF hash", msg, self.sign(encode(mh, embits, salt, h, other)), "invalid", ["WrongMgfHash"])
+        good = self.sign(encode(mh, embits, salt, h, mgfh))
+        if good[0] == 0:
+            self.add("signature with leading zero removed", msg, good[1:], "invalid", ["ModifiedSignature"])
+        else:
+            self.add("signature with appended zero", msg, good + b"\x00", "invalid", ["ModifiedSignature"])
+        s = int.from_bytes(good, "big")
+        if s + self.n < 1 << (8 * self.k):
+            self.add("signature plus modulus", msg, (s + self.n).to_bytes(self.k, "big"), "invalid", ["ModifiedSignature"])
+        else:
+            self.add("last signature bit flipped", msg, good[:-1] + bytes([good[-1] ^ 1]), "invalid", ["ModifiedSignature"])
+        self.add("empty signature", msg, b"", "invalid", ["ModifiedSignature"])
+        self.add("zero signature", msg, bytes(self.k), "invalid", ["ModifiedSignature"])
+        return {
+            "type": "RsassaPssVerify",
+            "keySize": self.bits,
+            "sha": HASH_NAMES[h],
+            "mgf": "MGF1",
+            "mgfSha": HASH_NAMES[mgfh],
+            "sLen": slen,
+            "publicKey": {"modulus": "00" + format(self.n, "x").rjust(2 * self.k, "0"), "publicExponent": "010001"},
+            "tests": self.tests,
+        }
+
+
+def main():
+    groups = [
+        Group(2048, "sha256", "sha256", 32),
+        Group(2048, "sha256", "sha256", 0),
+        Group(2048, "sha256", "sha1", 20),
+        Group(3072, "sha384", "sha384", 48),
+        Group(2049, "sha512", "sha512", 64),
+        Group(1024, "sha1", "sha1", 20, weak=True),
+    ]
+    test_groups = [g.build() for g in groups]
+    tc_id = 0
+    for group in test_groups:
+        for test in group["tests"]:
+            tc_id += 1
+            test["tcId"] = tc_id
+    out = {
+        "algorithm": "RSASSA-PSS",
+        "schema": "rsassa_pss_verify_schema.json",
+        "numberOfTests": tc_id,
+        "header": [
+            "Locally generated test vectors in the format of Project Wycheproof.",
+            "They are not part of Wycheproof: gen_rsa_pss_local_test.py computes them",
+            "with the Python standard library, independently of this package, and covers",
+            "valid signatures and modified trailers, padding, salt lengths, MGF hashes",
+            "and signature lengths.",
+        ],
+        "notes": {
+            "WeakHash": "SHA-1 with a 1024-bit key; a verifier may accept or refuse it.",
+            "ModifiedTrailer": "The trailer is not 0xbc.",
+            "ModifiedPadding": "The zero padding, the 0x01 separator or the unused leftmost bits are wrong.",
+            "ModifiedHash": "H does not match the message.",
+            "WrongSaltLength": "The salt length differs from sLen.",
+            "WrongMgfHash": "MGF1 uses another hash.",
+            "ModifiedSignature": "The signature is not a valid encoding of an integer below the modulus.",
+        },
+        "testGroups": test_groups,
+    }
+    json.dump(out, sys.stdout, indent=1)
+    sys.stdout.write("\n")
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,85 @@
+package pss
+
+import (
+	"crypto"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Tags of the records of MarshalSignatureTLV.
+const (
+	tlvHash byte = 1 + iota
+	tlvMGFHash
+	tlvSaltLength
+	tlvSignature
+)
+
+// MarshalSignatureTLV stores sig together with its parameters in a compact
+// binary form for applications that do not need ASN.1. The encoding is four
+// records, in this order, each a one byte tag, a four byte big-endian
+// length and the value:
+//
+//	tag 1: the hash function, one byte holding its crypto.Hash value
+//	tag 2: the MGF1 hash function, likewise; zero means the same as tag 1
+//	tag 3: the salt length, four bytes big-endian
+//	tag 4: the signature
+//
+// It panics if sLen is negative or hash or mgfHash does not fit in a byte.
+func MarshalSignatureTLV(hash crypto.Hash, mgfHash crypto.Hash, sLen int, sig []byte) []byte {
+	if sLen < 0 || uint64(sLen) > 0xFFFFFFFF {
+		panic("crypto/rsa: salt length out of range")
+	}
+	if hash > 0xFF || mgfHash > 0xFF {
+		panic("crypto/rsa: hash function out of range")
+	}
+	b := make([]byte, 0, 4*5+2+4+len(sig))
+	b = appendTLV(b, tlvHash, []byte{byte(hash)})
+	b = appendTLV(b, tlvMGFHash, []byte{byte(mgfHash)})
+	b = appendTLV(b, tlvSaltLength, binary.BigEndian.AppendUint32(nil, uint32(sLen)))
+	return appendTLV(b, tlvSignature, sig)
+}
+
+func appendTLV(b []byte, tag byte, value []byte) []byte {
+	b = append(b, tag)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(value)))
+	return append(b, value...)
+}
+
+var errTLVTruncated = errors.New("crypto/rsa: truncated signature record")
+
+// UnmarshalSignatureTLV parses the output of MarshalSignatureTLV. It
+// rejects truncated input, records out of order or of the wrong size and
+// trailing data.
+func UnmarshalSignatureTLV(b []byte) (hash crypto.Hash, mgfHash crypto.Hash, sLen int, sig []byte, err error) {
+	var values [4][]byte
+	for i := range values {
+		if len(b) < 5 {
+			return 0, 0, 0, nil, errTLVTruncated
+		}
+		tag, n := b[0], binary.BigEndian.Uint32(b[1:5])
+		b = b[5:]
+		if tag != tlvHash+byte(i) {
+			return 0, 0, 0, nil, fmt.Errorf("crypto/rsa: signature record has tag %d, want %d", tag, tlvHash+byte(i))
+		}
+		if uint64(n) > uint64(len(b)) {
+			return 0, 0, 0, nil, errTLVTruncated
+		}
+		values[i], b = b[:n], b[n:]
+	}
+	if len(b) != 0 {
+		return 0, 0, 0, nil, errors.New("crypto/rsa: trailing data after signature record")
+	}
+	if len(values[0]) != 1 || len(values[1]) != 1 || len(values[2]) != 4 {
+		return 0, 0, 0, nil, errors.New("crypto/rsa: malformed signature parameters")
+	}
+	hash, mgfHash = crypto.Hash(values[0][0]), crypto.Hash(values[1][0])
+	if hash == 0 {
+		return 0, 0, 0, nil, errors.New("crypto/rsa: signature record has no hash function")
+	}
+	l := binary.BigEndian.Uint32(values[2])
+	if uint64(l) > uint64(maxInt) {
+		return 0, 0, 0, nil, errors.New("crypto/rsa: salt length out of range")
+	}
+	return hash, mgfHash, int(l), values[3], nil
+}
@@ -0,0 +1,39 @@
+package pss
+
+import (
+	"bytes"
+	"crypto"
+	"testing"
+)
+
+func TestSignatureTLV(t *testing.T) {
+	sig := bytes.Repeat([]byte{0xA5}, 256)
+	b := MarshalSignatureTLV(crypto.SHA256, crypto.SHA1, 32, sig)
+	hash, mgfHash, sLen, got, err := UnmarshalSignatureTLV(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != crypto.SHA256 || mgfHash != crypto.SHA1 || sLen != 32 || !bytes.Equal(got, sig) {
+		t.Errorf("got %v, %v, %d, %x", hash, mgfHash, sLen, got)
+	}
+
+	for n := 0; n < len(b); n++ {
+		if _, _, _, _, err := UnmarshalSignatureTLV(b[:n]); err == nil {
+			t.Errorf("record truncated to %d bytes parsed", n)
+		}
+	}
+	if _, _, _, _, err := UnmarshalSignatureTLV(append(b, 0)); err == nil {
+		t.Errorf("record with trailing data parsed")
+	}
+	swapped := append([]byte(nil), b...)
+	swapped[0], swapped[6] = swapped[6], swapped[0]
+	if _, _, _, _, err := UnmarshalSignatureTLV(swapped); err == nil {
+		t.Errorf("records out of order parsed")
+	}
+
+	b = MarshalSignatureTLV(crypto.SHA512, 0, 0, nil)
+	hash, mgfHash, sLen, got, err = UnmarshalSignatureTLV(b)
+	if err != nil || hash != crypto.SHA512 || mgfHash != 0 || sLen != 0 || len(got) != 0 {
+		t.Errorf("empty signature: got %v, %v, %d, %x, %v", hash, mgfHash, sLen, got, err)
+	}
+}
@@ -0,0 +1,60 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+)
+
+// TryVerifyPSS verifies sig over message with each of the candidate hash
+// functions in turn and returns the first one under which the signature is
+// valid. It is meant for debugging interoperability with signers whose hash
+// function is unknown; if no candidate verifies, the returned error joins
+// the failure of every candidate.
+func TryVerifyPSS(pub *rsa.PublicKey, message []byte, sig []byte, candidates []crypto.Hash, sLen int) (crypto.Hash, error) {
+	if len(candidates) == 0 {
+		return 0, errors.New("crypto/rsa: no candidate hash functions")
+	}
+	var errs []error
+	for _, hash := range candidates {
+		if !hash.Available() {
+			errs = append(errs, fmt.Errorf("%v: hash function not available", hash))
+			continue
+		}
+		h := hash.New()
+		h.Write(message)
+		err := VerifyPSS(pub, hash, h.Sum(nil), sig, sLen)
+		if err == nil {
+			return hash, nil
+		}
+		errs = append(errs, fmt.Errorf("%v: %w", hash, err))
+	}
+	return 0, errors.Join(errs...)
+}
+
+// TryVerifyPSSSaltLengths verifies sig as a signature of hashed whose salt
+// length is not known and returns the salt length under which it is valid.
+// It first recovers the length from the signature as SaltLengthAuto does,
+// and if that fails tries each of the candidate lengths in turn, for
+// example 0 and the size of the hash. Like TryVerifyPSS it is meant for
+// debugging interoperability; if no length verifies, the returned error
+// joins every failure.
+func TryVerifyPSSSaltLengths(pub *rsa.PublicKey, hash crypto.Hash, hashed []byte, sig []byte, candidates []int, opts ...Option) (int, error) {
+	o := newOptions(opts)
+	found := -1
+	o.saltLengthFound = func(sLen int) { found = sLen }
+	var errs []error
+	for _, sLen := range append([]int{SaltLengthAuto}, candidates...) {
+		err := verifyPSS(pub, hash.New, hashed, sig, sLen, new(verifyScratch), o)
+		if err == nil {
+			return found, nil
+		}
+		if sLen == SaltLengthAuto {
+			errs = append(errs, fmt.Errorf("recovered salt length: %w", err))
+		} else {
+			errs = append(errs, fmt.Errorf("salt length %d: %w", sLen, err))
+		}
+	}
+	return 0, errors.Join(errs...)
+}
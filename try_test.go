@@ -0,0 +1,59 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"testing"
+)
+
+func TestTryVerifyPSS(t *testing.T) {
+	priv := testKey(t)
+	msg := []byte("which hash?")
+	hashed := sha512.Sum384(msg)
+	sig, err := SignPSS(rand.Reader, priv, crypto.SHA384, hashed[:], []byte("salt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	candidates := []crypto.Hash{crypto.SHA1, crypto.SHA256, crypto.SHA384, crypto.SHA512}
+	hash, err := TryVerifyPSS(&priv.PublicKey, msg, sig, candidates, 4)
+	if err != nil || hash != crypto.SHA384 {
+		t.Errorf("got %v, %v; want SHA-384", hash, err)
+	}
+
+	_, err = TryVerifyPSS(&priv.PublicKey, msg, sig, []crypto.Hash{crypto.SHA1, crypto.SHA256}, 4)
+	if !errors.Is(err, rsa.ErrVerification) {
+		t.Errorf("got %v, want an rsa.ErrVerification", err)
+	}
+}
+
+func TestTryVerifyPSSSaltLengths(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	hashed := sha256.Sum256([]byte("which salt length?"))
+	for _, saltLen := range []int{0, 17, sha256.Size, MaxSaltLength(pub, crypto.SHA256)} {
+		sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], saltLen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := TryVerifyPSSSaltLengths(pub, crypto.SHA256, hashed[:], sig, []int{0, sha256.Size})
+		if err != nil || got != saltLen {
+			t.Errorf("salt length %d: got %d, %v", saltLen, got, err)
+		}
+	}
+
+	sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The recovered length of 64 is rejected by the option and neither
+	// candidate verifies.
+	_, err = TryVerifyPSSSaltLengths(pub, crypto.SHA256, hashed[:], sig, []int{0, sha256.Size}, WithRejectSaltLongerThanHash())
+	if !errors.Is(err, ErrSaltTooLong) || !errors.Is(err, rsa.ErrVerification) {
+		t.Errorf("got %v, want ErrSaltTooLong and rsa.ErrVerification", err)
+	}
+}
@@ -1,8 +1,8 @@
 package pss
 
 import (
-	"crypto/rand"
 	"crypto/rsa"
+	"encoding"
 	"hash"
 	"io"
 	"math/big"
@@ -33,12 +33,36 @@ func mgf1XOR(out []byte, hash hash.Hash, seed []byte) {
 	var counter [4]byte
 	var digest []byte
 
+	// If the hash can save its state, absorb a long seed once and restore
+	// that state for each block instead of hashing the seed again. A seed
+	// shorter than a block is only buffered by the hash, and writing it
+	// again is cheaper than restoring the state.
+	var state []byte
+	u, canRestore := hash.(encoding.BinaryUnmarshaler)
+	if m, ok := hash.(encoding.BinaryMarshaler); ok && canRestore && len(seed) >= hash.BlockSize() {
+		hash.Reset()
+		hash.Write(seed)
+		state, _ = m.MarshalBinary()
+		hash.Reset()
+	}
+
 	done := 0
 	for done < len(out) {
-		hash.Write(seed)
+		// A hash that cannot restore its own state may have been left
+		// half-restored, so start it over and stop trying.
+		if state != nil && u.UnmarshalBinary(state) != nil {
+			state = nil
+			hash.Reset()
+		}
+		if state == nil {
+			hash.Write(seed)
+		}
 		hash.Write(counter[0:4])
 		digest = hash.Sum(digest[:0])
 		hash.Reset()
+		if len(digest) == 0 {
+			panic(errEmptyMGF1Block)
+		}
 
 		for i := 0; i < len(digest) && done < len(out); i++ {
 			out[done] ^= digest[i]
@@ -78,52 +102,43 @@ func encrypt(c *big.Int, pub *rsa.PublicKey, m *big.Int) *big.Int {
 	return c
 }
 
-// decrypt performs an RSA decryption, resulting in a plaintext integer. If a
-// random source is given, RSA blinding is used.
-func decrypt(random io.Reader, priv *rsa.PrivateKey, c *big.Int) (m *big.Int, err error) {
+// decrypt performs an RSA decryption, resulting in a plaintext integer. RSA
+// blinding with random is used unless WithoutBlinding is set; without that
+// option a nil random source is an error.
+func decrypt(random io.Reader, priv *rsa.PrivateKey, c *big.Int, o *options) (m *big.Int, err error) {
 	// TODO(agl): can we get away with reusing blinds?
-	if c.Cmp(priv.N) > 0 {
+	if c.Cmp(priv.N) >= 0 {
 		err = rsa.ErrDecryption
 		return
 	}
+	if random == nil && !o.noBlinding {
+		err = errNilRandom
+		return
+	}
 
 	var ir *big.Int
-	if random != nil {
-		// Blinding enabled. Blinding involves multiplying c by r^e.
-		// Then the decryption operation performs (m^e * r^e)^d mod n
-		// which equals mr mod n. The factor of r can then be removed
-		// by multiplying by the multiplicative inverse of r.
-
-		var r *big.Int
-
-		for {
-			r, err = rand.Int(random, priv.N)
-			if err != nil {
+	if !o.noBlinding {
+		var blinded *big.Int
+		o.startPhase(PhaseBlinding)
+		blinded, ir, err = blind(random, priv, c)
+		o.endPhase(PhaseBlinding)
+		if err != nil {
+			if o.blindingFailure != FallBackUnblinded {
 				return
 			}
-			if r.Cmp(bigZero) == 0 {
-				r = bigOne
-			}
-			var ok bool
-			ir, ok = modInverse(r, priv.N)
-			if ok {
-				break
-			}
+			o.logf("crypto/rsa: blinding failed, signing without blinding: %v", err)
+			blinded, ir, err = c, nil, nil
 		}
-		bigE := big.NewInt(int64(priv.E))
-		rpowe := new(big.Int).Exp(r, bigE, priv.N)
-		cCopy := new(big.Int).Set(c)
-		cCopy.Mul(cCopy, rpowe)
-		cCopy.Mod(cCopy, priv.N)
-		c = cCopy
+		c = blinded
 	}
 
-	if priv.Precomputed.Dp == nil {
-		m = new(big.Int).Exp(c, priv.D, priv.N)
+	o.startPhase(PhaseExponentiation)
+	if !hasCRTValues(priv) {
+		m = o.exp(c, priv.D, priv.N)
 	} else {
 		// We have the precalculated values needed for the CRT.
-		m = new(big.Int).Exp(c, priv.Precomputed.Dp, priv.Primes[0])
-		m2 := new(big.Int).Exp(c, priv.Precomputed.Dq, priv.Primes[1])
+		m = o.exp(c, priv.Precomputed.Dp, priv.Primes[0])
+		m2 := o.exp(c, priv.Precomputed.Dq, priv.Primes[1])
 		m.Sub(m, m2)
 		if m.Sign() < 0 {
 			m.Add(m, priv.Primes[0])
@@ -135,7 +150,7 @@ func decrypt(random io.Reader, priv *rsa.PrivateKey, c *big.Int) (m *big.Int, er
 
 		for i, values := range priv.Precomputed.CRTValues {
 			prime := priv.Primes[2+i]
-			m2.Exp(c, values.Exp, prime)
+			m2 = o.exp(c, values.Exp, prime)
 			m2.Sub(m2, m)
 			m2.Mul(m2, values.Coeff)
 			m2.Mod(m2, prime)
@@ -152,7 +167,54 @@ func decrypt(random io.Reader, priv *rsa.PrivateKey, c *big.Int) (m *big.Int, er
 		m.Mul(m, ir)
 		m.Mod(m, priv.N)
 	}
+	o.endPhase(PhaseExponentiation)
+
+	return
+}
+
+// hasCRTValues reports whether priv carries precomputed CRT values that are
+// consistent with its primes. A key that does not, for example one built by
+// hand with Precomputed set but no Primes, is used without the CRT.
+func hasCRTValues(priv *rsa.PrivateKey) bool {
+	pre := &priv.Precomputed
+	return pre.Dp != nil && pre.Dq != nil && pre.Qinv != nil &&
+		len(priv.Primes) == 2+len(pre.CRTValues)
+}
+
+// blind multiplies c by r^e for a random r and returns the result together
+// with the inverse of r, which removes the blinding factor from the
+// decrypted value.
+func blind(random io.Reader, priv *rsa.PrivateKey, c *big.Int) (blinded, ir *big.Int, err error) {
+	// Blinding enabled. Blinding involves multiplying c by r^e.
+	// Then the decryption operation performs (m^e * r^e)^d mod n
+	// which equals mr mod n. The factor of r can then be removed
+	// by multiplying by the multiplicative inverse of r.
 
+	var r *big.Int
+
+	if priv.E <= 0 {
+		err = errNonPositiveExponent
+		return
+	}
+	for {
+		r, err = randInt(random, priv.N)
+		if err != nil {
+			return
+		}
+		if r.Cmp(bigZero) == 0 {
+			r = bigOne
+		}
+		var ok bool
+		ir, ok = modInverse(r, priv.N)
+		if ok {
+			break
+		}
+	}
+	bigE := big.NewInt(int64(priv.E))
+	rpowe := new(big.Int).Exp(r, bigE, priv.N)
+	blinded = new(big.Int).Set(c)
+	blinded.Mul(blinded, rpowe)
+	blinded.Mod(blinded, priv.N)
 	return
 }
 
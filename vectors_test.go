@@ -0,0 +1,170 @@
+package pss
+
+import (
+	"bufio"
+	"crypto"
+	"crypto/rsa"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// rspVector is one test case of a response file in the layout of the NIST
+// CAVP SigGenPSS and SigVerPSS files.
+type rspVector struct {
+	// ID names the vector by file and line for error messages.
+	ID      string
+	N, E, D *big.Int
+	Hash    crypto.Hash
+	Msg     []byte
+	S       []byte
+	Salt    []byte
+	// Result is "P" or "F" for verification vectors and empty for
+	// signature generation vectors, which are all valid.
+	Result string
+}
+
+var rspHashes = map[string]crypto.Hash{
+	"SHA1":       crypto.SHA1,
+	"SHA224":     crypto.SHA224,
+	"SHA256":     crypto.SHA256,
+	"SHA384":     crypto.SHA384,
+	"SHA512":     crypto.SHA512,
+	"SHA512/224": crypto.SHA512_224,
+	"SHA512/256": crypto.SHA512_256,
+}
+
+// loadRSP parses a response file. The key values n, e and d apply to every
+// vector that follows them; a vector starts with its SHAAlg line. Lines
+// starting with #, section headers such as [mod = 2048] and keys the test
+// does not use are skipped.
+func loadRSP(t *testing.T, path string) []rspVector {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var vectors []rspVector
+	var n, e, d *big.Int
+	var v *rspVector
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == '#' || text[0] == '[' {
+			continue
+		}
+		key, value, ok := strings.Cut(text, " = ")
+		if !ok {
+			t.Fatalf("%s:%d: malformed line %q", path, line, text)
+		}
+		id := fmt.Sprintf("%s:%d", filepath.Base(path), line)
+		parseInt := func() *big.Int {
+			i, ok := new(big.Int).SetString(value, 16)
+			if !ok {
+				t.Fatalf("%s: bad integer %q", id, value)
+			}
+			return i
+		}
+		parseBytes := func() []byte {
+			b, err := hex.DecodeString(value)
+			if err != nil {
+				t.Fatalf("%s: %v", id, err)
+			}
+			return b
+		}
+		switch key {
+		case "n":
+			n = parseInt()
+		case "e":
+			e = parseInt()
+		case "d":
+			d = parseInt()
+		case "SHAAlg":
+			hash, ok := rspHashes[value]
+			if !ok {
+				t.Fatalf("%s: unknown hash %q", id, value)
+			}
+			vectors = append(vectors, rspVector{ID: id, N: n, E: e, D: d, Hash: hash})
+			v = &vectors[len(vectors)-1]
+		case "Msg", "S", "SaltVal", "Result":
+			if v == nil {
+				t.Fatalf("%s: %s before SHAAlg", id, key)
+			}
+			switch key {
+			case "Msg":
+				v.Msg = parseBytes()
+			case "S":
+				v.S = parseBytes()
+			case "SaltVal":
+				// NIST writes an empty salt as 00.
+				if value != "00" {
+					v.Salt = parseBytes()
+				}
+			case "Result":
+				v.Result, _, _ = strings.Cut(value, " ")
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return vectors
+}
+
+// TestRSPVectors runs every vector in the .rsp files of testdata: each
+// signature must verify if and only if it is valid, and a signature
+// generation vector must be reproduced by signing with its salt.
+func TestRSPVectors(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.rsp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no .rsp files in testdata")
+	}
+	for _, file := range files {
+		for _, v := range loadRSP(t, file) {
+			pub := &rsa.PublicKey{N: v.N, E: int(v.E.Int64())}
+			h := v.Hash.New()
+			h.Write(v.Msg)
+			hashed := h.Sum(nil)
+			err := VerifyPSS(pub, v.Hash, hashed, v.S, len(v.Salt))
+			if valid := v.Result != "F"; valid != (err == nil) {
+				t.Errorf("%s: valid = %v, but verification returned %v", v.ID, valid, err)
+			}
+			if v.D == nil || v.Result != "" {
+				continue
+			}
+			priv := &rsa.PrivateKey{PublicKey: *pub, D: v.D}
+			sig, err := SignPSSFixed(priv, v.Hash, hashed, v.Salt)
+			if err != nil {
+				t.Errorf("%s: signing: %v", v.ID, err)
+				continue
+			}
+			if !compareBytes(sig, v.S) {
+				t.Errorf("%s: signature does not match", v.ID)
+			}
+		}
+	}
+}
+
+func TestLoadRSPSkipsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "unknown.rsp")
+	data := "# CAVS 11.4\n[mod = 1024]\n\nn = 0b\ne = 03\nSHAAlg = SHA256\nMsg = 00\nS = 01\nSaltVal = 00\nEM = 02\nResult = F (1 - Message changed)\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	vectors := loadRSP(t, path)
+	if len(vectors) != 1 {
+		t.Fatalf("got %d vectors, want 1", len(vectors))
+	}
+	if v := vectors[0]; v.Hash != crypto.SHA256 || v.Result != "F" || len(v.S) != 1 || v.Salt != nil {
+		t.Errorf("got %+v", v)
+	}
+}
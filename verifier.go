@@ -0,0 +1,87 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"hash"
+	"sync"
+)
+
+// A Verifier verifies RSASSA-PSS signatures made with one public key and hash
+// function. Unlike VerifyPSS it keeps the encoded message buffer, the big.Int
+// scratch space and the hash instances of finished verifications in a pool and
+// reuses them, which reduces allocations when verifying at a high rate.
+// A Verifier is safe for concurrent use.
+type Verifier struct {
+	pub  *rsa.PublicKey
+	hash crypto.Hash
+	opts *options
+	pool sync.Pool
+}
+
+type verifierState struct {
+	verifyScratch
+	hashes [2]hash.Hash
+	next   int
+}
+
+// newHash hands out the pooled hash instances in turn, reset. A verification
+// uses one instance for the mask and one for H', so each gets its own.
+func (st *verifierState) newHash() hash.Hash {
+	h := st.hashes[st.next%len(st.hashes)]
+	st.next++
+	h.Reset()
+	return h
+}
+
+// NewVerifier returns a Verifier for signatures made by the private key
+// belonging to pub using the given hash function. The options apply to
+// every verification.
+func NewVerifier(pub *rsa.PublicKey, hash crypto.Hash, opts ...Option) *Verifier {
+	v := &Verifier{pub: pub, hash: hash, opts: newOptions(opts)}
+	v.pool.New = v.newState
+	return v
+}
+
+func (v *Verifier) newState() interface{} {
+	return &verifierState{hashes: [2]hash.Hash{v.hash.New(), v.hash.New()}}
+}
+
+// Verify verifies sig as VerifyPSS does.
+func (v *Verifier) Verify(hashed []byte, sig []byte, sLen int) error {
+	st := v.pool.Get().(*verifierState)
+	defer v.pool.Put(st)
+	st.next = 0
+	return verifyPSS(v.pub, st.newHash, hashed, sig, sLen, &st.verifyScratch, v.opts)
+}
+
+// VerifyPSSHash is like VerifyPSS but computes the mask and H' with h, a
+// hash instance owned by the caller, instead of creating new ones, so that
+// one instance can serve any number of verifications in turn. h is reset
+// before use and its state afterwards is unspecified. h must not be used
+// concurrently, by the caller or by another VerifyPSSHash call.
+func VerifyPSSHash(pub *rsa.PublicKey, h hash.Hash, hashed []byte, sig []byte, sLen int, opts ...Option) error {
+	o := newOptions(opts)
+	newHash := func() hash.Hash {
+		h.Reset()
+		return h
+	}
+	return verifyPSS(pub, newHash, hashed, sig, sLen, new(verifyScratch), o)
+}
+
+// A VerifyScratch holds the big.Int values and the encoded message buffer
+// of a verification, so that VerifyPSSScratch can reuse them instead of
+// allocating them anew, as a device verifying update after update in a
+// loop may want to. The zero value is ready to use. A VerifyScratch must
+// not be shared between goroutines; give each its own, or use a Verifier.
+type VerifyScratch struct {
+	sc verifyScratch
+}
+
+// VerifyPSSScratch is like VerifyPSS but keeps its big.Int values and
+// encoded message buffer in sc. After the first verification with a key of
+// a given size, later ones with sc allocate none of them. The contents of
+// sc after the call are unspecified.
+func VerifyPSSScratch(pub *rsa.PublicKey, hash crypto.Hash, hashed []byte, sig []byte, sLen int, sc *VerifyScratch, opts ...Option) error {
+	return verifyPSS(pub, hash.New, hashed, sig, sLen, &sc.sc, newOptions(opts))
+}
@@ -0,0 +1,249 @@
+package pss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"sync"
+	"testing"
+)
+
+func TestVerifier(t *testing.T) {
+	priv := testKey(t)
+	hashed := sha256.Sum256([]byte("verifier"))
+	sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := NewVerifier(&priv.PublicKey, crypto.SHA256)
+	for i := 0; i < 3; i++ {
+		if err := v.Verify(hashed[:], sig, sha256.Size); err != nil {
+			t.Errorf("iteration %d: %v", i, err)
+		}
+	}
+	// Each verification must use its pooled hash instances alone; run
+	// with -race to check.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := v.Verify(hashed[:], sig, sha256.Size); err != nil {
+				t.Errorf("concurrent verification: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	sig[len(sig)/2] ^= 1
+	if err := v.Verify(hashed[:], sig, sha256.Size); err == nil {
+		t.Errorf("corrupted signature verified")
+	}
+}
+
+func TestVerifyPSSHash(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	hashed := sha256.Sum256([]byte("caller-owned hash"))
+	sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := sha256.New()
+	h.Write([]byte("left over from an earlier use"))
+	for i, sLen := range []int{sha256.Size, SaltLengthAuto, sha256.Size} {
+		if err := VerifyPSSHash(pub, h, hashed[:], sig, sLen); err != nil {
+			t.Errorf("iteration %d: %v", i, err)
+		}
+	}
+	if err := VerifyPSSHash(pub, h, hashed[:], sig, 0); err == nil {
+		t.Errorf("wrong salt length verified")
+	}
+	if err := VerifyPSSHash(pub, h, hashed[:], sig, sha256.Size); err != nil {
+		t.Errorf("after a failed verification: %v", err)
+	}
+}
+
+func TestVerifyPSSScratch(t *testing.T) {
+	priv := testKey(t)
+	pub := &priv.PublicKey
+	hashed := sha256.Sum256([]byte("reused scratch"))
+	sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sc VerifyScratch
+	for i, sLen := range []int{sha256.Size, SaltLengthAuto, sha256.Size} {
+		if err := VerifyPSSScratch(pub, crypto.SHA256, hashed[:], sig, sLen, &sc); err != nil {
+			t.Errorf("iteration %d: %v", i, err)
+		}
+	}
+	bad := append([]byte(nil), sig...)
+	bad[len(bad)/2] ^= 1
+	if err := VerifyPSSScratch(pub, crypto.SHA256, hashed[:], bad, sha256.Size, &sc); err == nil {
+		t.Errorf("corrupted signature verified")
+	}
+
+	// After the first use the scratch values keep their storage.
+	verify := func() {
+		VerifyPSSScratch(pub, crypto.SHA256, hashed[:], sig, sha256.Size, &sc)
+	}
+	verify()
+	em, s, m := &sc.sc.em[:1][0], &sc.sc.s.Bits()[:1][0], &sc.sc.m.Bits()[:1][0]
+	withScratch := testing.AllocsPerRun(10, verify)
+	if &sc.sc.em[:1][0] != em || &sc.sc.s.Bits()[:1][0] != s || &sc.sc.m.Bits()[:1][0] != m {
+		t.Errorf("scratch values reallocated after the first verification")
+	}
+	without := testing.AllocsPerRun(10, func() {
+		VerifyPSS(pub, crypto.SHA256, hashed[:], sig, sha256.Size)
+	})
+	if withScratch >= without {
+		t.Errorf("%v allocations with a scratch, %v without", withScratch, without)
+	}
+}
+
+func benchmarkSignature(b *testing.B) ([]byte, []byte) {
+	priv := testKey(b)
+	hashed := sha256.Sum256([]byte("benchmark"))
+	sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return hashed[:], sig
+}
+
+func BenchmarkVerifyPSS(b *testing.B) {
+	hashed, sig := benchmarkSignature(b)
+	pub := &testKey(b).PublicKey
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := VerifyPSS(pub, crypto.SHA256, hashed, sig, sha256.Size); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVerifyPSSHash(b *testing.B) {
+	hashed, sig := benchmarkSignature(b)
+	pub := &testKey(b).PublicKey
+	h := sha256.New()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := VerifyPSSHash(pub, h, hashed, sig, sha256.Size); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkVerifyPSSScratch reuses one VerifyScratch; the allocations left
+// after the first iteration are those of big.Int.Exp's internal temporaries
+// and the hash instances, not of the values kept in the scratch. The
+// scratch-allocs/op metric counts the iterations after which the storage
+// of s, m, e or the encoded message has moved, and is 0.
+func BenchmarkVerifyPSSScratch(b *testing.B) {
+	hashed, sig := benchmarkSignature(b)
+	pub := &testKey(b).PublicKey
+	var sc VerifyScratch
+	if err := VerifyPSSScratch(pub, crypto.SHA256, hashed, sig, sha256.Size, &sc); err != nil {
+		b.Fatal(err)
+	}
+	type pointers struct {
+		s, m, e *big.Word
+		em      *byte
+	}
+	storage := func() pointers {
+		return pointers{&sc.sc.s.Bits()[:1][0], &sc.sc.m.Bits()[:1][0], &sc.sc.e.Bits()[:1][0], &sc.sc.em[:1][0]}
+	}
+	before := storage()
+	moved := 0
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := VerifyPSSScratch(pub, crypto.SHA256, hashed, sig, sha256.Size, &sc); err != nil {
+			b.Fatal(err)
+		}
+		if after := storage(); after != before {
+			moved++
+			before = after
+		}
+	}
+	b.ReportMetric(float64(moved)/float64(b.N), "scratch-allocs/op")
+	if moved != 0 {
+		b.Errorf("scratch storage moved in %d of %d iterations", moved, b.N)
+	}
+}
+
+func BenchmarkVerifier(b *testing.B) {
+	hashed, sig := benchmarkSignature(b)
+	v := NewVerifier(&testKey(b).PublicKey, crypto.SHA256)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := v.Verify(hashed, sig, sha256.Size); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// forbidRandReads replaces crypto/rand.Reader with a reader that counts and
+// fails every read, and returns a function that restores it and returns the
+// number of reads.
+func forbidRandReads() func() int {
+	saved := rand.Reader
+	counter := &countingReader{r: errReader{}}
+	rand.Reader = counter
+	return func() int {
+		rand.Reader = saved
+		return counter.calls
+	}
+}
+
+// Verification only uses the public key and must never read randomness,
+// which makes it cheap and usable without a random source.
+func TestVerifyPSSReadsNoRandomness(t *testing.T) {
+	hashed := sha256.Sum256([]byte("no randomness"))
+	priv := testKey(t)
+	sig, err := SignPSSAutoSalt(rand.Reader, priv, crypto.SHA256, hashed[:], sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := &priv.PublicKey
+	v := NewVerifier(pub, crypto.SHA256)
+
+	restore := forbidRandReads()
+	errs := []error{
+		VerifyPSS(pub, crypto.SHA256, hashed[:], sig, sha256.Size),
+		VerifyPSS(pub, crypto.SHA256, hashed[:], sig, SaltLengthAuto, WithConstantTimeVerify()),
+		v.Verify(hashed[:], sig, sha256.Size),
+		VerifyPSSReader(pub, crypto.SHA256, hashed[:], bytes.NewReader(sig), sha256.Size),
+	}
+	if reads := restore(); reads != 0 {
+		t.Errorf("verification read from crypto/rand.Reader %d times", reads)
+	}
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("verification %d: %v", i, err)
+		}
+	}
+}
+
+func BenchmarkVerifyPSSNoRandomness(b *testing.B) {
+	hashed, sig := benchmarkSignature(b)
+	pub := &testKey(b).PublicKey
+	restore := forbidRandReads()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := VerifyPSS(pub, crypto.SHA256, hashed, sig, sha256.Size); err != nil {
+			restore()
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	if reads := restore(); reads != 0 {
+		b.Fatalf("verification read from crypto/rand.Reader %d times", reads)
+	}
+}
@@ -0,0 +1,112 @@
+package pss
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// wycheproofFile is a file of RSASSA-PSS verification vectors in the format
+// of Project Wycheproof, rsassa_pss_verify_schema.json.
+type wycheproofFile struct {
+	Algorithm  string `json:"algorithm"`
+	TestGroups []struct {
+		KeySize int    `json:"keySize"`
+		SHA     string `json:"sha"`
+		MGF     string `json:"mgf"`
+		MGFSHA  string `json:"mgfSha"`
+		SLen    int    `json:"sLen"`
+		// Newer files hold the key in PublicKey, older ones in N and E.
+		PublicKey struct {
+			Modulus        string `json:"modulus"`
+			PublicExponent string `json:"publicExponent"`
+		} `json:"publicKey"`
+		N     string `json:"n"`
+		E     string `json:"e"`
+		Tests []struct {
+			TcID    int      `json:"tcId"`
+			Comment string   `json:"comment"`
+			Msg     string   `json:"msg"`
+			Sig     string   `json:"sig"`
+			Result  string   `json:"result"`
+			Flags   []string `json:"flags"`
+		} `json:"tests"`
+	} `json:"testGroups"`
+}
+
+var wycheproofHashes = map[string]crypto.Hash{
+	"SHA-1":       crypto.SHA1,
+	"SHA-224":     crypto.SHA224,
+	"SHA-256":     crypto.SHA256,
+	"SHA-384":     crypto.SHA384,
+	"SHA-512":     crypto.SHA512,
+	"SHA-512/224": crypto.SHA512_224,
+	"SHA-512/256": crypto.SHA512_256,
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// TestWycheproofVectors runs every rsa_pss_*_test.json file in testdata.
+// Valid signatures must verify and invalid ones must not; acceptable ones
+// may do either. The upstream Wycheproof files can be copied in as they
+// are. rsa_pss_local_test.json is not one of them: it is written by
+// testdata/gen_rsa_pss_local_test.py, which encodes and signs with its own
+// seeded keys and salts.
+func TestWycheproofVectors(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "rsa_pss_*_test.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no rsa_pss_*_test.json files in testdata")
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var vectors wycheproofFile
+		if err := json.Unmarshal(data, &vectors); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		name := filepath.Base(file)
+		for _, group := range vectors.TestGroups {
+			hash, ok := wycheproofHashes[group.SHA]
+			mgfHash, mgfOK := wycheproofHashes[group.MGFSHA]
+			if !ok || !mgfOK || group.MGF != "MGF1" {
+				t.Logf("%s: skipping group with %s, %s with %s", name, group.SHA, group.MGF, group.MGFSHA)
+				continue
+			}
+			modulus, exponent := group.PublicKey.Modulus, group.PublicKey.PublicExponent
+			if modulus == "" {
+				modulus, exponent = group.N, group.E
+			}
+			pub := &rsa.PublicKey{
+				N: new(big.Int).SetBytes(mustHex(t, modulus)),
+				E: int(new(big.Int).SetBytes(mustHex(t, exponent)).Int64()),
+			}
+			for _, test := range group.Tests {
+				h := hash.New()
+				h.Write(mustHex(t, test.Msg))
+				err := VerifyPSS(pub, hash, h.Sum(nil), mustHex(t, test.Sig), group.SLen, WithMGFHash(mgfHash))
+				switch {
+				case test.Result == "valid" && err != nil:
+					t.Errorf("%s #%d (%s): valid signature rejected: %v", name, test.TcID, test.Comment, err)
+				case test.Result == "invalid" && err == nil:
+					t.Errorf("%s #%d (%s, %v): invalid signature accepted", name, test.TcID, test.Comment, test.Flags)
+				}
+			}
+		}
+	}
+}